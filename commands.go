@@ -0,0 +1,158 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/internal/generate"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/internal/snapshot"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// commands are the subcommands that the binary supports alongside serving the
+// provider
+var commands = map[string]func(args []string) error{
+	"export":   runExport,
+	"restore":  runRestore,
+	"generate": runGenerate,
+}
+
+// stringsFlag is a flag that can be repeated
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(v string) error {
+	*f = append(*f, v)
+	return nil
+}
+
+// clientFlags registers the flags that configure the Nebraska client. They
+// fall back to the same environment variables as the provider.
+func clientFlags(fs *flag.FlagSet) func() *nebraska.Client {
+	endpoint := fs.String("endpoint", "", "The address of the Nebraska server. Can also be set with the environment variable NEBRASKA_ENDPOINT. (default \"http://localhost:8000\")")
+	username := fs.String("username", "", "The username for authentication to the Nebraska server. Can also be set with the environment variable NEBRASKA_USERNAME.")
+	password := fs.String("password", "", "The password for authentication to the Nebraska server. Can also be set with the environment variable NEBRASKA_PASSWORD.")
+	bearerToken := fs.String("bearer-token", "", "The bearer token for authentication to the Nebraska server. Can also be set with the environment variable NEBRASKA_BEARER_TOKEN.")
+
+	return func() *nebraska.Client {
+		return nebraska.New(
+			flagOrEnv(*endpoint, "NEBRASKA_ENDPOINT", "http://localhost:8000"),
+			"terraform-provider-nebraska/"+version,
+			flagOrEnv(*username, "NEBRASKA_USERNAME", ""),
+			flagOrEnv(*password, "NEBRASKA_PASSWORD", ""),
+			flagOrEnv(*bearerToken, "NEBRASKA_BEARER_TOKEN", ""),
+		)
+	}
+}
+
+func flagOrEnv(v, key, def string) string {
+	if v != "" {
+		return v
+	}
+	if v := os.Getenv(key); v != "" {
+		return v
+	}
+
+	return def
+}
+
+func runExport(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	newClient := clientFlags(fs)
+	var appIDs stringsFlag
+	fs.Var(&appIDs, "application", "An application to export. Can be repeated. Exports all applications if omitted.")
+	output := fs.String("output", "-", "The file to write the snapshot to, - for stdout.")
+	fs.Parse(args)
+
+	s, err := snapshot.Export(newClient(), appIDs)
+	if err != nil {
+		return err
+	}
+
+	w := io.Writer(os.Stdout)
+	if *output != "-" {
+		f, err := os.Create(*output)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	return s.Write(w)
+}
+
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	newClient := clientFlags(fs)
+	input := fs.String("input", "-", "The file to read the snapshot from, - for stdin.")
+	fs.Parse(args)
+
+	r := io.Reader(os.Stdin)
+	if *input != "-" {
+		f, err := os.Open(*input)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	s, err := snapshot.Read(r)
+	if err != nil {
+		return err
+	}
+
+	// Print the ids that were restored even if restoring fails part way, so
+	// that the objects that were created can be found
+	ids, restoreErr := snapshot.Restore(newClient(), s)
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(ids); err != nil {
+		return err
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("restore failed: %w", restoreErr)
+	}
+
+	return nil
+}
+
+func runGenerate(args []string) error {
+	fs := flag.NewFlagSet("generate", flag.ExitOnError)
+	newClient := clientFlags(fs)
+	appID := fs.String("application", "", "The application to generate configuration for. Can also be set with the environment variable NEBRASKA_APPLICATION_ID.")
+	outputDir := fs.String("output-dir", ".", "The directory to write the configuration to.")
+	fs.Parse(args)
+
+	id := flagOrEnv(*appID, "NEBRASKA_APPLICATION_ID", "")
+	if id == "" {
+		return fmt.Errorf("application: required flag is not set")
+	}
+
+	s, err := snapshot.Export(newClient(), []string{id})
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(*outputDir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range generate.Generate(s.Applications[0]) {
+		path := filepath.Join(*outputDir, f.Name)
+		if err := os.WriteFile(path, f.Content, 0o644); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stderr, "Wrote", path)
+	}
+
+	return nil
+}
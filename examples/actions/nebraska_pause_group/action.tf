@@ -0,0 +1,19 @@
+action "nebraska_pause_group" "stable" {
+  config {
+    group_id = nebraska_group.stable.id
+  }
+}
+
+# Invoke with: terraform apply -invoke=action.nebraska_pause_group.stable
+
+# Or pause the group whenever the package of its channel changes
+resource "terraform_data" "stable_package" {
+  input = nebraska_channel.stable.package_id
+
+  lifecycle {
+    action_trigger {
+      events  = [before_update]
+      actions = [action.nebraska_pause_group.stable]
+    }
+  }
+}
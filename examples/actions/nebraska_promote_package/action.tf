@@ -0,0 +1,13 @@
+data "nebraska_package" "previous" {
+  version = "3510.2.1"
+  arch    = "amd64"
+}
+
+action "nebraska_promote_package" "rollback" {
+  config {
+    channel_id = nebraska_channel.stable.id
+    package_id = data.nebraska_package.previous.id
+  }
+}
+
+# Invoke with: terraform apply -invoke=action.nebraska_promote_package.rollback
@@ -0,0 +1,7 @@
+action "nebraska_resume_group" "stable" {
+  config {
+    group_id = nebraska_group.stable.id
+  }
+}
+
+# Invoke with: terraform apply -invoke=action.nebraska_resume_group.stable
@@ -0,0 +1,4 @@
+data "nebraska_channels" "channels" {
+  name_regex = "^(stable|beta)$"
+  arch       = "amd64"
+}
@@ -1,3 +1,7 @@
 data "nebraska_group" "group" {
   name = "Edge (AMD64)"
 }
+
+data "nebraska_group" "by_track" {
+  track = "custom-track"
+}
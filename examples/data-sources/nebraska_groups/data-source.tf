@@ -0,0 +1,3 @@
+data "nebraska_groups" "groups" {
+  name_regex = "AMD64"
+}
@@ -0,0 +1,5 @@
+data "nebraska_packages" "packages" {
+  arch           = "amd64"
+  type           = "flatcar"
+  version_prefix = "2942."
+}
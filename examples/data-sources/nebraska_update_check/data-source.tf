@@ -0,0 +1,5 @@
+data "nebraska_update_check" "stable" {
+  track   = "stable"
+  version = "3374.2.0"
+  arch    = "amd64"
+}
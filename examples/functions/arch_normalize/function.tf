@@ -0,0 +1,9 @@
+variable "arch" {
+  type    = string
+  default = "arm64"
+}
+
+data "nebraska_package" "package" {
+  version = "2942.1.0"
+  arch    = provider::nebraska::arch_normalize(var.arch) # "aarch64"
+}
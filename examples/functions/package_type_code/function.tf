@@ -0,0 +1,4 @@
+# The type codes that the Nebraska API returns for packages
+locals {
+  flatcar_type = provider::nebraska::package_type_code("flatcar") # 1
+}
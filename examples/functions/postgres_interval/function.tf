@@ -0,0 +1,6 @@
+resource "nebraska_group" "group" {
+  name                          = "custom-group"
+  policy_max_updates_per_period = 10
+  policy_period_interval        = provider::nebraska::postgres_interval("90m") # "90 minutes"
+  policy_update_timeout         = provider::nebraska::postgres_interval("2h")  # "2 hours"
+}
@@ -0,0 +1,11 @@
+data "nebraska_packages" "all" {
+  arch = "amd64"
+}
+
+# The packages that are newer than 3510.2.0
+locals {
+  newer_packages = [
+    for p in data.nebraska_packages.all.packages : p
+    if provider::nebraska::version_compare(p.version, "3510.2.0") > 0
+  ]
+}
@@ -0,0 +1,8 @@
+list "nebraska_channel" "amd64" {
+  provider = nebraska
+
+  config {
+    arch       = "amd64"
+    name_regex = "^custom-"
+  }
+}
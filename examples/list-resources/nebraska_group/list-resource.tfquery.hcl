@@ -0,0 +1,8 @@
+list "nebraska_group" "amd64" {
+  provider         = nebraska
+  include_resource = true
+
+  config {
+    arch = "amd64"
+  }
+}
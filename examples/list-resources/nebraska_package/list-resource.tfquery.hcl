@@ -0,0 +1,9 @@
+list "nebraska_package" "flatcar" {
+  provider = nebraska
+
+  config {
+    arch          = "amd64"
+    type          = "flatcar"
+    version_range = ">=3510.0.0 <3602.0.0"
+  }
+}
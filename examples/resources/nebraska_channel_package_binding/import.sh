@@ -0,0 +1,6 @@
+# Bindings can be imported by the ids of their channels separated by commas,
+# which uses the provider's application_id
+terraform import nebraska_channel_package_binding.example 2c4e9ee4-4e0d-4c4b-9e0e-1f0f8d2b5a6c,7b1d0f4e-8a3c-4f5e-9d2b-3c6a1e0f9b8d
+
+# or by application id and channel ids
+terraform import nebraska_channel_package_binding.example e96281a6-d1af-4bde-9a0a-97b76e56dc57/2c4e9ee4-4e0d-4c4b-9e0e-1f0f8d2b5a6c,7b1d0f4e-8a3c-4f5e-9d2b-3c6a1e0f9b8d
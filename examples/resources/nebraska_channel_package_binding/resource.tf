@@ -0,0 +1,16 @@
+data "nebraska_package" "amd64" {
+  version = "3602.2.0"
+  arch    = "amd64"
+}
+
+data "nebraska_package" "arm64" {
+  version = "3602.2.0"
+  arch    = "aarch64"
+}
+
+resource "nebraska_channel_package_binding" "stable" {
+  bindings = {
+    (nebraska_channel.stable_amd64.id) = data.nebraska_package.amd64.id
+    (nebraska_channel.stable_arm64.id) = data.nebraska_package.arm64.id
+  }
+}
@@ -0,0 +1,5 @@
+# Groups can be imported by id, which uses the provider's application_id
+terraform import nebraska_group.example 2c4e9ee4-4e0d-4c4b-9e0e-1f0f8d2b5a6c
+
+# or by application id and group id
+terraform import nebraska_group.example e96281a6-d1af-4bde-9a0a-97b76e56dc57/2c4e9ee4-4e0d-4c4b-9e0e-1f0f8d2b5a6c
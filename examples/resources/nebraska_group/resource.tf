@@ -13,3 +13,21 @@ resource "nebraska_group" "group" {
   name       = "custom-group"
   channel_id = nebraska_channel.channel.id
 }
+
+resource "nebraska_group" "verified" {
+  name       = "custom-verified-group"
+  track      = "custom-verified"
+  channel_id = nebraska_channel.channel.id
+
+  # Fail the apply if a machine on 2905.2.0 isn't offered the channel's package
+  verify_update_check {
+    from_version = "2905.2.0"
+    on_failure   = "fail"
+  }
+}
+
+resource "nebraska_group" "protected" {
+  name                = "custom-protected-group"
+  channel_id          = nebraska_channel.channel.id
+  deletion_protection = true
+}
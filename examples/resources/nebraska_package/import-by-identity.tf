@@ -0,0 +1,7 @@
+import {
+  to = nebraska_package.example
+  identity = {
+    application_id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+    id             = "2c4e9ee4-4e0d-4c4b-9e0e-1f0f8d2b5a6c"
+  }
+}
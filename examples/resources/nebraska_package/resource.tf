@@ -12,3 +12,11 @@ resource "nebraska_package" "test" {
   }
 }
 
+
+resource "nebraska_package" "archived" {
+  version = "2905.2.0"
+  url     = "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/"
+
+  # Keep the package in Nebraska when it's removed from the configuration
+  on_delete = "retain"
+}
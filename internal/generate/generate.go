@@ -0,0 +1,225 @@
+// Package generate renders the packages, channels and groups of a Nebraska
+// application as Terraform configuration, along with the import blocks that
+// bring the existing objects under management.
+package generate
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/internal/snapshot"
+	"github.com/zclconf/go-cty/cty"
+)
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// File is a generated configuration file
+type File struct {
+	Name    string
+	Content []byte
+}
+
+// resource is a generated resource and the object it imports
+type resource struct {
+	Type string
+	Name string
+	ID   string
+}
+
+// Generate renders the application as the files packages.tf, channels.tf,
+// groups.tf and imports.tf. Resource names are derived from the package
+// versions and the channel and group names, so they're stable across runs.
+func Generate(app snapshot.Application) []File {
+	packages := append([]snapshot.Package(nil), app.Packages...)
+	sort.SliceStable(packages, func(i, j int) bool {
+		if packages[i].Version != packages[j].Version {
+			return packages[i].Version < packages[j].Version
+		}
+		if packages[i].Arch != packages[j].Arch {
+			return packages[i].Arch < packages[j].Arch
+		}
+		return packages[i].ID < packages[j].ID
+	})
+	channels := append([]snapshot.Channel(nil), app.Channels...)
+	sort.SliceStable(channels, func(i, j int) bool {
+		if channels[i].Name != channels[j].Name {
+			return channels[i].Name < channels[j].Name
+		}
+		if channels[i].Arch != channels[j].Arch {
+			return channels[i].Arch < channels[j].Arch
+		}
+		return channels[i].ID < channels[j].ID
+	})
+	groups := append([]snapshot.Group(nil), app.Groups...)
+	sort.SliceStable(groups, func(i, j int) bool {
+		if groups[i].Name != groups[j].Name {
+			return groups[i].Name < groups[j].Name
+		}
+		if groups[i].Track != groups[j].Track {
+			return groups[i].Track < groups[j].Track
+		}
+		return groups[i].ID < groups[j].ID
+	})
+
+	packageNames, channelNames, groupNames := newNamer(), newNamer(), newNamer()
+	refs := map[string]resource{}
+	var resources []resource
+	for _, p := range packages {
+		r := resource{Type: "nebraska_package", Name: packageNames.name("package", p.Version, p.Arch), ID: p.ID}
+		refs[p.ID] = r
+		resources = append(resources, r)
+	}
+	for _, ch := range channels {
+		r := resource{Type: "nebraska_channel", Name: channelNames.name("channel", ch.Name, ch.Arch), ID: ch.ID}
+		refs[ch.ID] = r
+		resources = append(resources, r)
+	}
+	for _, g := range groups {
+		r := resource{Type: "nebraska_group", Name: groupNames.name("group", g.Name), ID: g.ID}
+		refs[g.ID] = r
+		resources = append(resources, r)
+	}
+
+	header := fmt.Sprintf("# Generated by terraform-provider-nebraska from application %s\n\n", app.ID)
+
+	packagesFile := hclwrite.NewEmptyFile()
+	for i, p := range packages {
+		if i > 0 {
+			packagesFile.Body().AppendNewline()
+		}
+		body := packagesFile.Body().AppendNewBlock("resource", []string{"nebraska_package", refs[p.ID].Name}).Body()
+		body.SetAttributeValue("application_id", cty.StringVal(app.ID))
+		body.SetAttributeValue("version", cty.StringVal(p.Version))
+		body.SetAttributeValue("url", cty.StringVal(p.URL))
+		body.SetAttributeValue("arch", cty.StringVal(p.Arch))
+		body.SetAttributeValue("type", cty.StringVal(p.Type))
+		setOptionalString(body, "filename", p.Filename)
+		setOptionalString(body, "description", p.Description)
+		setOptionalString(body, "size", p.Size)
+		setOptionalString(body, "hash", p.Hash)
+		if len(p.ChannelsBlacklist) > 0 {
+			// Blacklisted channels are referenced by id because referencing
+			// the channel resources could create a dependency cycle between
+			// packages and channels
+			blacklist := make([]cty.Value, 0, len(p.ChannelsBlacklist))
+			for _, id := range p.ChannelsBlacklist {
+				blacklist = append(blacklist, cty.StringVal(id))
+			}
+			body.SetAttributeValue("channels_blacklist", cty.ListVal(blacklist))
+		}
+		if p.FlatcarActionSHA256 != "" {
+			body.AppendNewline()
+			action := body.AppendNewBlock("flatcar_action", nil).Body()
+			action.SetAttributeValue("sha256", cty.StringVal(p.FlatcarActionSHA256))
+		}
+	}
+
+	channelsFile := hclwrite.NewEmptyFile()
+	for i, ch := range channels {
+		if i > 0 {
+			channelsFile.Body().AppendNewline()
+		}
+		body := channelsFile.Body().AppendNewBlock("resource", []string{"nebraska_channel", refs[ch.ID].Name}).Body()
+		body.SetAttributeValue("application_id", cty.StringVal(app.ID))
+		body.SetAttributeValue("name", cty.StringVal(ch.Name))
+		body.SetAttributeValue("arch", cty.StringVal(ch.Arch))
+		setOptionalString(body, "color", ch.Color)
+		setReference(body, "package_id", refs, ch.PackageID)
+	}
+
+	groupsFile := hclwrite.NewEmptyFile()
+	for i, g := range groups {
+		if i > 0 {
+			groupsFile.Body().AppendNewline()
+		}
+		body := groupsFile.Body().AppendNewBlock("resource", []string{"nebraska_group", refs[g.ID].Name}).Body()
+		body.SetAttributeValue("application_id", cty.StringVal(app.ID))
+		body.SetAttributeValue("name", cty.StringVal(g.Name))
+		setOptionalString(body, "description", g.Description)
+		body.SetAttributeValue("track", cty.StringVal(g.Track))
+		setReference(body, "channel_id", refs, g.ChannelID)
+		body.SetAttributeValue("policy_updates_enabled", cty.BoolVal(g.PolicyUpdatesEnabled))
+		body.SetAttributeValue("policy_safe_mode", cty.BoolVal(g.PolicySafeMode))
+		body.SetAttributeValue("policy_office_hours", cty.BoolVal(g.PolicyOfficeHours))
+		setOptionalString(body, "policy_timezone", g.PolicyTimezone)
+		body.SetAttributeValue("policy_period_interval", cty.StringVal(g.PolicyPeriodInterval))
+		body.SetAttributeValue("policy_max_updates_per_period", cty.NumberIntVal(int64(g.PolicyMaxUpdatesPerPeriod)))
+		body.SetAttributeValue("policy_update_timeout", cty.StringVal(g.PolicyUpdateTimeout))
+	}
+
+	importsFile := hclwrite.NewEmptyFile()
+	for i, r := range resources {
+		if i > 0 {
+			importsFile.Body().AppendNewline()
+		}
+		body := importsFile.Body().AppendNewBlock("import", nil).Body()
+		body.SetAttributeTraversal("to", hcl.Traversal{
+			hcl.TraverseRoot{Name: r.Type},
+			hcl.TraverseAttr{Name: r.Name},
+		})
+		body.SetAttributeValue("id", cty.StringVal(app.ID+"/"+r.ID))
+	}
+
+	return []File{
+		{Name: "packages.tf", Content: append([]byte(header), packagesFile.Bytes()...)},
+		{Name: "channels.tf", Content: append([]byte(header), channelsFile.Bytes()...)},
+		{Name: "groups.tf", Content: append([]byte(header), groupsFile.Bytes()...)},
+		{Name: "imports.tf", Content: append([]byte(header), importsFile.Bytes()...)},
+	}
+}
+
+func setOptionalString(body *hclwrite.Body, name, value string) {
+	if value == "" {
+		return
+	}
+	body.SetAttributeValue(name, cty.StringVal(value))
+}
+
+// setReference sets the attribute to the id of the generated resource for
+// the object, or to the id itself if the object isn't part of the application
+func setReference(body *hclwrite.Body, name string, refs map[string]resource, id string) {
+	if id == "" {
+		return
+	}
+	r, ok := refs[id]
+	if !ok {
+		body.SetAttributeValue(name, cty.StringVal(id))
+		return
+	}
+	body.SetAttributeTraversal(name, hcl.Traversal{
+		hcl.TraverseRoot{Name: r.Type},
+		hcl.TraverseAttr{Name: r.Name},
+		hcl.TraverseAttr{Name: "id"},
+	})
+}
+
+// namer derives unique resource names from object names
+type namer struct {
+	used map[string]bool
+}
+
+func newNamer() *namer {
+	return &namer{used: map[string]bool{}}
+}
+
+// name joins the parts into a valid resource name, which is prefixed when it
+// doesn't start with a letter and suffixed with a counter when it's taken
+func (n *namer) name(prefix string, parts ...string) string {
+	name := strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(strings.Join(parts, "_")), "_"), "_")
+	if name == "" || name[0] < 'a' || name[0] > 'z' {
+		name = strings.TrimSuffix(prefix+"_"+name, "_")
+	}
+
+	unique := name
+	for i := 2; n.used[unique]; i++ {
+		unique = name + "_" + strconv.Itoa(i)
+	}
+	n.used[unique] = true
+
+	return unique
+}
@@ -0,0 +1,136 @@
+package generate
+
+import (
+	"testing"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/internal/snapshot"
+	"gotest.tools/assert"
+)
+
+func TestGenerate(t *testing.T) {
+	app := snapshot.Application{
+		ID: "e96281a6-d1af-4bde-9a0a-97b76e56dc57",
+		Packages: []snapshot.Package{
+			{ID: "p2", Version: "3033.2.0", Arch: "amd64", Type: "flatcar", URL: "https://update.release.flatcar-linux.net/amd64-usr/3033.2.0/", ChannelsBlacklist: []string{"c2"}},
+			{ID: "p1", Version: "2905.2.0", Arch: "amd64", Type: "flatcar", URL: "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/", Hash: "r3nufcxgMTZaxYEqL+x2zIoeClk=", FlatcarActionSHA256: "LIkAKVZY2EJFiwTmltiJZLFLA5xT/FodbjVgqkyF/y8="},
+		},
+		Channels: []snapshot.Channel{
+			{ID: "c2", Name: "Stable!", Arch: "amd64", PackageID: "p1"},
+			{ID: "c1", Name: "stable", Arch: "amd64", Color: "#000000", PackageID: "p2"},
+		},
+		Groups: []snapshot.Group{
+			{ID: "g1", Name: "Stable (AMD64)", Track: "stable", ChannelID: "c1", PolicyUpdatesEnabled: true, PolicyPeriodInterval: "1 minutes", PolicyMaxUpdatesPerPeriod: 10, PolicyUpdateTimeout: "60 minutes"},
+			{ID: "g2", Name: "1st", Track: "first", ChannelID: "deleted", PolicyPeriodInterval: "1 minutes", PolicyMaxUpdatesPerPeriod: 1, PolicyUpdateTimeout: "60 minutes"},
+		},
+	}
+
+	files := map[string]string{}
+	for _, f := range Generate(app) {
+		files[f.Name] = string(f.Content)
+	}
+
+	assert.Equal(t, files["packages.tf"], `# Generated by terraform-provider-nebraska from application e96281a6-d1af-4bde-9a0a-97b76e56dc57
+
+resource "nebraska_package" "package_2905_2_0_amd64" {
+  application_id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  version        = "2905.2.0"
+  url            = "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/"
+  arch           = "amd64"
+  type           = "flatcar"
+  hash           = "r3nufcxgMTZaxYEqL+x2zIoeClk="
+
+  flatcar_action {
+    sha256 = "LIkAKVZY2EJFiwTmltiJZLFLA5xT/FodbjVgqkyF/y8="
+  }
+}
+
+resource "nebraska_package" "package_3033_2_0_amd64" {
+  application_id     = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  version            = "3033.2.0"
+  url                = "https://update.release.flatcar-linux.net/amd64-usr/3033.2.0/"
+  arch               = "amd64"
+  type               = "flatcar"
+  channels_blacklist = ["c2"]
+}
+`)
+
+	assert.Equal(t, files["channels.tf"], `# Generated by terraform-provider-nebraska from application e96281a6-d1af-4bde-9a0a-97b76e56dc57
+
+resource "nebraska_channel" "stable_amd64" {
+  application_id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  name           = "Stable!"
+  arch           = "amd64"
+  package_id     = nebraska_package.package_2905_2_0_amd64.id
+}
+
+resource "nebraska_channel" "stable_amd64_2" {
+  application_id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  name           = "stable"
+  arch           = "amd64"
+  color          = "#000000"
+  package_id     = nebraska_package.package_3033_2_0_amd64.id
+}
+`)
+
+	assert.Equal(t, files["groups.tf"], `# Generated by terraform-provider-nebraska from application e96281a6-d1af-4bde-9a0a-97b76e56dc57
+
+resource "nebraska_group" "group_1st" {
+  application_id                = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  name                          = "1st"
+  track                         = "first"
+  channel_id                    = "deleted"
+  policy_updates_enabled        = false
+  policy_safe_mode              = false
+  policy_office_hours           = false
+  policy_period_interval        = "1 minutes"
+  policy_max_updates_per_period = 1
+  policy_update_timeout         = "60 minutes"
+}
+
+resource "nebraska_group" "stable_amd64" {
+  application_id                = "e96281a6-d1af-4bde-9a0a-97b76e56dc57"
+  name                          = "Stable (AMD64)"
+  track                         = "stable"
+  channel_id                    = nebraska_channel.stable_amd64_2.id
+  policy_updates_enabled        = true
+  policy_safe_mode              = false
+  policy_office_hours           = false
+  policy_period_interval        = "1 minutes"
+  policy_max_updates_per_period = 10
+  policy_update_timeout         = "60 minutes"
+}
+`)
+
+	assert.Equal(t, files["imports.tf"], `# Generated by terraform-provider-nebraska from application e96281a6-d1af-4bde-9a0a-97b76e56dc57
+
+import {
+  to = nebraska_package.package_2905_2_0_amd64
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/p1"
+}
+
+import {
+  to = nebraska_package.package_3033_2_0_amd64
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/p2"
+}
+
+import {
+  to = nebraska_channel.stable_amd64
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/c2"
+}
+
+import {
+  to = nebraska_channel.stable_amd64_2
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/c1"
+}
+
+import {
+  to = nebraska_group.group_1st
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/g2"
+}
+
+import {
+  to = nebraska_group.stable_amd64
+  id = "e96281a6-d1af-4bde-9a0a-97b76e56dc57/g1"
+}
+`)
+}
@@ -0,0 +1,122 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ action.ActionWithConfigure = &groupUpdatesAction{}
+
+// NewPauseGroupAction returns the nebraska_pause_group action
+func NewPauseGroupAction() action.Action {
+	return &groupUpdatesAction{
+		name:    "pause_group",
+		enabled: false,
+	}
+}
+
+// NewResumeGroupAction returns the nebraska_resume_group action
+func NewResumeGroupAction() action.Action {
+	return &groupUpdatesAction{
+		name:    "resume_group",
+		enabled: true,
+	}
+}
+
+// groupUpdatesAction enables or disables the updates of a group
+type groupUpdatesAction struct {
+	client  *apiClient
+	name    string
+	enabled bool
+}
+
+type groupUpdatesActionModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	GroupID       types.String `tfsdk:"group_id"`
+}
+
+func (a *groupUpdatesAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_" + a.name
+}
+
+func (a *groupUpdatesAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	description := "Pauses the updates of a group, for example during an incident, by disabling `policy_updates_enabled`."
+	if a.enabled {
+		description = "Resumes the updates of a group by enabling `policy_updates_enabled`."
+	}
+
+	resp.Schema = schema.Schema{
+		MarkdownDescription: description + " A `nebraska_group` that manages the group will plan to change it back.",
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the application the group belongs to. Defaults to the provider's `application_id`.",
+			},
+			"group_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID of the group.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (a *groupUpdatesAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+func (a *groupUpdatesAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	verb, summary, state := "Paused", "Couldn't pause group", "paused"
+	if a.enabled {
+		verb, summary, state = "Resumed", "Couldn't resume group", "enabled"
+	}
+
+	var config groupUpdatesActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, a.client)
+	if err != nil {
+		resp.Diagnostics.AddError(summary, err.Error())
+		return
+	}
+	id := config.GroupID.ValueString()
+
+	group, err := a.client.WithContext(ctx).GetGroup(appID, id)
+	if err != nil {
+		if err == nebraska.ErrNotFound {
+			resp.Diagnostics.AddError(summary, fmt.Sprintf("couldn't find group %s", id))
+			return
+		}
+		resp.Diagnostics.AddError(summary, err.Error())
+		return
+	}
+	if group.PolicyUpdatesEnabled == a.enabled {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Updates of group %q (%s) are already %s", group.Name, id, state),
+		})
+		return
+	}
+
+	input := updateGroupInputFromGroup(*group)
+	input.PolicyUpdatesEnabled = a.enabled
+	if _, err := a.client.WithContext(ctx).UpdateGroup(appID, id, input); err != nil {
+		resp.Diagnostics.AddError(summary, err.Error())
+		return
+	}
+
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("%s updates of group %q (%s)", verb, group.Name, id),
+	})
+}
@@ -0,0 +1,47 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestGroupUpdatesAction(t *testing.T) {
+	groupPath := "/api/apps/" + nebraska.FlatcarApplicationID + "/groups/g1"
+	group := nebraska.Group{
+		ID:                   "g1",
+		Name:                 "stable",
+		ChannelID:            "c1",
+		PolicyUpdatesEnabled: true,
+		PolicyTimezone:       "Europe/London",
+		Track:                "stable",
+	}
+	config := map[string]tftypes.Value{
+		"group_id": tftypes.NewValue(tftypes.String, "g1"),
+	}
+
+	server := newTestServer(t, map[string]any{groupPath: group})
+	messages, diags := testInvokeAction(t, server, "nebraska_pause_group", config)
+	assert.Equal(t, len(diags), 0)
+	assert.DeepEqual(t, messages, []string{`Paused updates of group "stable" (g1)`})
+	assert.Equal(t, len(server.writes), 1)
+	assert.Equal(t, server.writes[0].Path, groupPath)
+	assert.Equal(t, server.writes[0].Body["policy_updates_enabled"], false)
+	// The other settings are kept
+	assert.Equal(t, server.writes[0].Body["channel_id"], "c1")
+	assert.Equal(t, server.writes[0].Body["policy_timezone"], "Europe/London")
+	assert.Equal(t, server.writes[0].Body["track"], "stable")
+
+	server = newTestServer(t, map[string]any{groupPath: group})
+	messages, diags = testInvokeAction(t, server, "nebraska_resume_group", config)
+	assert.Equal(t, len(diags), 0)
+	assert.DeepEqual(t, messages, []string{`Updates of group "stable" (g1) are already enabled`})
+	assert.Equal(t, len(server.writes), 0)
+
+	server = newTestServer(t, map[string]any{})
+	_, diags = testInvokeAction(t, server, "nebraska_pause_group", config)
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Detail, "couldn't find group g1")
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/action/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ action.ActionWithConfigure = &promotePackageAction{}
+
+// NewPromotePackageAction returns the nebraska_promote_package action
+func NewPromotePackageAction() action.Action {
+	return &promotePackageAction{}
+}
+
+type promotePackageAction struct {
+	client *apiClient
+}
+
+type promotePackageActionModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	ChannelID     types.String `tfsdk:"channel_id"`
+	PackageID     types.String `tfsdk:"package_id"`
+}
+
+func (a *promotePackageAction) Metadata(ctx context.Context, req action.MetadataRequest, resp *action.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_promote_package"
+}
+
+func (a *promotePackageAction) Schema(ctx context.Context, req action.SchemaRequest, resp *action.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Points a channel at a package, for example to force it back to a previous version. The package must have the same arch as the channel and the channel mustn't be in its `channels_blacklist`. A `nebraska_channel` that manages the channel will plan to change it back.",
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the application the channel and package belong to. Defaults to the provider's `application_id`.",
+			},
+			"channel_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID of the channel.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"package_id": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "ID of the package the channel provides.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+		},
+	}
+}
+
+func (a *promotePackageAction) Configure(ctx context.Context, req action.ConfigureRequest, resp *action.ConfigureResponse) {
+	a.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+func (a *promotePackageAction) Invoke(ctx context.Context, req action.InvokeRequest, resp *action.InvokeResponse) {
+	var config promotePackageActionModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, a.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't promote package", err.Error())
+		return
+	}
+	channelID := config.ChannelID.ValueString()
+	packageID := config.PackageID.ValueString()
+
+	channel, err := a.client.WithContext(ctx).GetChannel(appID, channelID)
+	if err != nil {
+		if err == nebraska.ErrNotFound {
+			resp.Diagnostics.AddError("Couldn't promote package", fmt.Sprintf("couldn't find channel %s", channelID))
+			return
+		}
+		resp.Diagnostics.AddError("Couldn't promote package", err.Error())
+		return
+	}
+	pkg, err := a.client.WithContext(ctx).GetPackage(appID, packageID)
+	if err != nil {
+		if err == nebraska.ErrNotFound {
+			resp.Diagnostics.AddError("Couldn't promote package", fmt.Sprintf("couldn't find package %s", packageID))
+			return
+		}
+		resp.Diagnostics.AddError("Couldn't promote package", err.Error())
+		return
+	}
+
+	if pkg.Arch != channel.Arch {
+		resp.Diagnostics.AddError("Couldn't promote package", fmt.Sprintf("package %s (%s) has a different arch to channel %q (%s)", pkg.Version, pkg.Arch.String(), channel.Name, channel.Arch.String()))
+		return
+	}
+	if slices.Contains(pkg.ChannelsBlacklist, channelID) {
+		resp.Diagnostics.AddError("Couldn't promote package", fmt.Sprintf("channel %q (%s) is in the channels_blacklist of package %s", channel.Name, channelID, pkg.Version))
+		return
+	}
+	if channel.PackageID == packageID {
+		resp.SendProgress(action.InvokeProgressEvent{
+			Message: fmt.Sprintf("Channel %q (%s) already provides package %s", channel.Name, channelID, pkg.Version),
+		})
+		return
+	}
+
+	input := updateChannelInputFromChannel(appID, *channel)
+	input.PackageID = packageID
+	if _, err := a.client.WithContext(ctx).UpdateChannel(appID, channelID, input); err != nil {
+		resp.Diagnostics.AddError("Couldn't promote package", err.Error())
+		return
+	}
+
+	previous := "no package"
+	if channel.Package != nil {
+		previous = "package " + channel.Package.Version
+	} else if channel.PackageID != "" {
+		previous = "package " + channel.PackageID
+	}
+	resp.SendProgress(action.InvokeProgressEvent{
+		Message: fmt.Sprintf("Channel %q (%s) now provides package %s instead of %s", channel.Name, channelID, pkg.Version, previous),
+	})
+}
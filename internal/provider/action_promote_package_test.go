@@ -0,0 +1,60 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestPromotePackageAction(t *testing.T) {
+	appPath := "/api/apps/" + nebraska.FlatcarApplicationID
+	responses := map[string]any{
+		appPath + "/channels/c1": nebraska.Channel{
+			ID:        "c1",
+			Name:      "stable",
+			Arch:      nebraska.ArchAMD64,
+			Color:     "#000000",
+			PackageID: "p1",
+			Package:   &nebraska.Package{ID: "p1", Version: "3602.2.0"},
+		},
+		appPath + "/packages/p1": nebraska.Package{ID: "p1", Version: "3602.2.0", Arch: nebraska.ArchAMD64},
+		appPath + "/packages/p2": nebraska.Package{ID: "p2", Version: "3510.2.1", Arch: nebraska.ArchAMD64},
+		appPath + "/packages/p3": nebraska.Package{ID: "p3", Version: "3510.2.1", Arch: nebraska.ArchAArch64},
+		appPath + "/packages/p4": nebraska.Package{ID: "p4", Version: "3510.2.0", Arch: nebraska.ArchAMD64, ChannelsBlacklist: []string{"c1"}},
+	}
+	config := func(packageID string) map[string]tftypes.Value {
+		return map[string]tftypes.Value{
+			"channel_id": tftypes.NewValue(tftypes.String, "c1"),
+			"package_id": tftypes.NewValue(tftypes.String, packageID),
+		}
+	}
+
+	server := newTestServer(t, responses)
+	messages, diags := testInvokeAction(t, server, "nebraska_promote_package", config("p2"))
+	assert.Equal(t, len(diags), 0)
+	assert.DeepEqual(t, messages, []string{`Channel "stable" (c1) now provides package 3510.2.1 instead of package 3602.2.0`})
+	assert.Equal(t, len(server.writes), 1)
+	assert.Equal(t, server.writes[0].Path, appPath+"/channels/c1")
+	assert.Equal(t, server.writes[0].Body["package_id"], "p2")
+	assert.Equal(t, server.writes[0].Body["color"], "#000000")
+
+	server = newTestServer(t, responses)
+	messages, diags = testInvokeAction(t, server, "nebraska_promote_package", config("p1"))
+	assert.Equal(t, len(diags), 0)
+	assert.DeepEqual(t, messages, []string{`Channel "stable" (c1) already provides package 3602.2.0`})
+	assert.Equal(t, len(server.writes), 0)
+
+	server = newTestServer(t, responses)
+	_, diags = testInvokeAction(t, server, "nebraska_promote_package", config("p3"))
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Detail, `package 3510.2.1 (aarch64) has a different arch to channel "stable" (amd64)`)
+	assert.Equal(t, len(server.writes), 0)
+
+	server = newTestServer(t, responses)
+	_, diags = testInvokeAction(t, server, "nebraska_promote_package", config("p4"))
+	assert.Equal(t, len(diags), 1)
+	assert.Equal(t, diags[0].Detail, `channel "stable" (c1) is in the channels_blacklist of package 3510.2.0`)
+	assert.Equal(t, len(server.writes), 0)
+}
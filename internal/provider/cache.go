@@ -0,0 +1,342 @@
+package provider
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// readCache serves the reads of an application's channels, groups and
+// packages from a single list of each, so that refreshing many resources
+// doesn't make a request for each of them. It's dropped on any write to the
+// server through the provider, and lasts as long as the provider process, which is a single
+// Terraform operation.
+type readCache struct {
+	mu       sync.Mutex
+	channels map[string]*cachedList[nebraska.Channel]
+	groups   map[string]*cachedList[nebraska.Group]
+	packages map[string]*cachedList[nebraska.Package]
+}
+
+// readCacheKey identifies a read cache. Each set of credentials has its own,
+// because they may not be allowed to read the same things.
+type readCacheKey struct {
+	endpoint    string
+	username    string
+	password    string
+	bearerToken string
+}
+
+// readCaches are the read caches of the Nebraska servers by endpoint and
+// credentials. The framework and SDK parts of the provider and its aliases
+// share them, so that writes through any of them drop the reads of all.
+var (
+	readCachesMu sync.Mutex
+	readCaches   = map[readCacheKey]*readCache{}
+)
+
+// sharedReadCache returns the read cache of the Nebraska server at the
+// endpoint for the credentials
+func sharedReadCache(endpoint, username, password, bearerToken string) *readCache {
+	readCachesMu.Lock()
+	defer readCachesMu.Unlock()
+
+	key := readCacheKey{endpoint: endpoint, username: username, password: password, bearerToken: bearerToken}
+	c, ok := readCaches[key]
+	if !ok {
+		c = newReadCache()
+		readCaches[key] = c
+	}
+
+	return c
+}
+
+// invalidateReadCaches drops the read caches of the Nebraska server at the
+// endpoint, whatever their credentials
+func invalidateReadCaches(endpoint string) {
+	readCachesMu.Lock()
+	defer readCachesMu.Unlock()
+
+	for key, c := range readCaches {
+		if key.endpoint == endpoint {
+			c.invalidate()
+		}
+	}
+}
+
+// cachedList is a list of objects that's requested by the first read that
+// needs it, while any other reads wait for it. It keeps the counts that the
+// server returned with it, which differ if the server truncated it.
+type cachedList[T any] struct {
+	once       sync.Once
+	items      []T
+	count      int
+	totalCount int
+	err        error
+}
+
+func newReadCache() *readCache {
+	c := &readCache{}
+	c.invalidate()
+
+	return c
+}
+
+// invalidate drops everything in the cache
+func (c *readCache) invalidate() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.channels = map[string]*cachedList[nebraska.Channel]{}
+	c.groups = map[string]*cachedList[nebraska.Group]{}
+	c.packages = map[string]*cachedList[nebraska.Package]{}
+}
+
+// cachedItems returns the items of the application from the cache, or lists
+// them if they aren't in it, with the counts that the server returned with
+// them. Lists that fail aren't kept.
+func cachedItems[T any](c *readCache, lists *map[string]*cachedList[T], appID string, list func() ([]T, int, int, error)) ([]T, int, int, error) {
+	c.mu.Lock()
+	l, ok := (*lists)[appID]
+	if !ok {
+		l = &cachedList[T]{}
+		(*lists)[appID] = l
+	}
+	c.mu.Unlock()
+
+	l.once.Do(func() {
+		l.items, l.count, l.totalCount, l.err = list()
+	})
+	if l.err != nil {
+		c.mu.Lock()
+		if (*lists)[appID] == l {
+			delete(*lists, appID)
+		}
+		c.mu.Unlock()
+		return nil, 0, 0, l.err
+	}
+
+	return slices.Clone(l.items), l.count, l.totalCount, nil
+}
+
+// complete returns an error if the server truncated a list, because objects
+// that aren't in it can't be told apart from those that don't exist
+func complete(kind string, count, totalCount int) error {
+	if count != totalCount {
+		return fmt.Errorf("GET %s returned %d/%d %s. We don't paginate.", kind, count, totalCount, kind)
+	}
+
+	return nil
+}
+
+// find returns the item with the id, or ErrNotFound like Nebraska
+func find[T any](items []T, id string, itemID func(*T) string) (*T, error) {
+	for i := range items {
+		if itemID(&items[i]) == id {
+			return &items[i], nil
+		}
+	}
+
+	return nil, nebraska.ErrNotFound
+}
+
+func (c *apiClient) cachedChannels(appID string) ([]nebraska.Channel, int, int, error) {
+	return cachedItems(c.cache, &c.cache.channels, appID, func() ([]nebraska.Channel, int, int, error) {
+		page, err := c.Client.ListChannels(appID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return page.Channels, page.Count, page.TotalCount, nil
+	})
+}
+
+func (c *apiClient) cachedGroups(appID string) ([]nebraska.Group, int, int, error) {
+	return cachedItems(c.cache, &c.cache.groups, appID, func() ([]nebraska.Group, int, int, error) {
+		page, err := c.Client.ListGroups(appID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return page.Groups, page.Count, page.TotalCount, nil
+	})
+}
+
+func (c *apiClient) cachedPackages(appID string) ([]nebraska.Package, int, int, error) {
+	return cachedItems(c.cache, &c.cache.packages, appID, func() ([]nebraska.Package, int, int, error) {
+		page, err := c.Client.ListPackages(appID)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return page.Packages, page.Count, page.TotalCount, nil
+	})
+}
+
+// GetChannel gets the channel from the cache, if it's enabled
+func (c *apiClient) GetChannel(appID, id string) (*nebraska.Channel, error) {
+	if c.cache == nil {
+		return c.Client.GetChannel(appID, id)
+	}
+	channels, count, totalCount, err := c.cachedChannels(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := complete("channels", count, totalCount); err != nil {
+		return nil, err
+	}
+
+	return find(channels, id, func(ch *nebraska.Channel) string { return ch.ID })
+}
+
+// ListChannels lists the channels from the cache, if it's enabled
+func (c *apiClient) ListChannels(appID string) (*nebraska.ChannelPage, error) {
+	if c.cache == nil {
+		return c.Client.ListChannels(appID)
+	}
+	channels, count, totalCount, err := c.cachedChannels(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nebraska.ChannelPage{Channels: channels, Count: count, TotalCount: totalCount}, nil
+}
+
+// GetGroup gets the group from the cache, if it's enabled
+func (c *apiClient) GetGroup(appID, id string) (*nebraska.Group, error) {
+	if c.cache == nil {
+		return c.Client.GetGroup(appID, id)
+	}
+	groups, count, totalCount, err := c.cachedGroups(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := complete("groups", count, totalCount); err != nil {
+		return nil, err
+	}
+
+	return find(groups, id, func(g *nebraska.Group) string { return g.ID })
+}
+
+// ListGroups lists the groups from the cache, if it's enabled
+func (c *apiClient) ListGroups(appID string) (*nebraska.GroupPage, error) {
+	if c.cache == nil {
+		return c.Client.ListGroups(appID)
+	}
+	groups, count, totalCount, err := c.cachedGroups(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nebraska.GroupPage{Groups: groups, Count: count, TotalCount: totalCount}, nil
+}
+
+// GetPackage gets the package from the cache, if it's enabled
+func (c *apiClient) GetPackage(appID, id string) (*nebraska.Package, error) {
+	if c.cache == nil {
+		return c.Client.GetPackage(appID, id)
+	}
+	packages, count, totalCount, err := c.cachedPackages(appID)
+	if err != nil {
+		return nil, err
+	}
+	if err := complete("packages", count, totalCount); err != nil {
+		return nil, err
+	}
+
+	return find(packages, id, func(p *nebraska.Package) string { return p.ID })
+}
+
+// ListPackages lists the packages from the cache, if it's enabled
+func (c *apiClient) ListPackages(appID string) (*nebraska.PackagePage, error) {
+	if c.cache == nil {
+		return c.Client.ListPackages(appID)
+	}
+	packages, count, totalCount, err := c.cachedPackages(appID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &nebraska.PackagePage{Packages: packages, Count: count, TotalCount: totalCount}, nil
+}
+
+// SearchPackages searches the packages in the cache, if it's enabled, the
+// way Nebraska does
+func (c *apiClient) SearchPackages(appID, search string) (*nebraska.PackagePage, error) {
+	if c.cache == nil {
+		return c.Client.SearchPackages(appID, search)
+	}
+	packages, count, totalCount, err := c.cachedPackages(appID)
+	if err != nil {
+		return nil, err
+	}
+	// Packages that match the search may be past the end of the list
+	if err := complete("packages", count, totalCount); err != nil {
+		return nil, err
+	}
+	packages = slices.DeleteFunc(packages, func(p nebraska.Package) bool {
+		return !strings.Contains(strings.ToLower(p.Version), strings.ToLower(search))
+	})
+
+	return &nebraska.PackagePage{Packages: packages, Count: len(packages), TotalCount: len(packages)}, nil
+}
+
+// write drops the caches of the server after a write, whether or not it
+// succeeded, including those of other provider configurations, even if this
+// one doesn't cache reads
+func (c *apiClient) write(err error) error {
+	invalidateReadCaches(c.BaseURL)
+
+	return err
+}
+
+// AddChannel adds the channel and drops the cache
+func (c *apiClient) AddChannel(appID string, input *nebraska.AddChannelInput) (*nebraska.Channel, error) {
+	ch, err := c.Client.AddChannel(appID, input)
+	return ch, c.write(err)
+}
+
+// UpdateChannel updates the channel and drops the cache
+func (c *apiClient) UpdateChannel(appID, id string, input *nebraska.UpdateChannelInput) (*nebraska.Channel, error) {
+	ch, err := c.Client.UpdateChannel(appID, id, input)
+	return ch, c.write(err)
+}
+
+// DeleteChannel deletes the channel and drops the cache
+func (c *apiClient) DeleteChannel(appID, id string) error {
+	return c.write(c.Client.DeleteChannel(appID, id))
+}
+
+// AddGroup adds the group and drops the cache
+func (c *apiClient) AddGroup(appID string, input *nebraska.AddGroupInput) (*nebraska.Group, error) {
+	g, err := c.Client.AddGroup(appID, input)
+	return g, c.write(err)
+}
+
+// UpdateGroup updates the group and drops the cache
+func (c *apiClient) UpdateGroup(appID, id string, input *nebraska.UpdateGroupInput) (*nebraska.Group, error) {
+	g, err := c.Client.UpdateGroup(appID, id, input)
+	return g, c.write(err)
+}
+
+// DeleteGroup deletes the group and drops the cache
+func (c *apiClient) DeleteGroup(appID, id string) error {
+	return c.write(c.Client.DeleteGroup(appID, id))
+}
+
+// AddPackage adds the package and drops the cache
+func (c *apiClient) AddPackage(appID string, input *nebraska.AddPackageInput) (*nebraska.Package, error) {
+	p, err := c.Client.AddPackage(appID, input)
+	return p, c.write(err)
+}
+
+// UpdatePackage updates the package and drops the cache
+func (c *apiClient) UpdatePackage(appID, id string, input *nebraska.UpdatePackageInput) (*nebraska.Package, error) {
+	p, err := c.Client.UpdatePackage(appID, id, input)
+	return p, c.write(err)
+}
+
+// DeletePackage deletes the package and drops the cache
+func (c *apiClient) DeletePackage(appID, id string) error {
+	return c.write(c.Client.DeletePackage(appID, id))
+}
@@ -0,0 +1,153 @@
+package provider
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest"
+	"gotest.tools/assert"
+)
+
+func TestReadCache(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	defer fake.Close()
+	target, err := url.Parse(fake.URL)
+	assert.NilError(t, err)
+
+	var mu sync.Mutex
+	requests := map[string]int{}
+	proxy := httputil.NewSingleHostReverseProxy(target)
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		requests[r.Method+" "+r.URL.Path]++
+		mu.Unlock()
+		proxy.ServeHTTP(w, r)
+	}))
+	defer s.Close()
+
+	c := &apiClient{
+		Client: nebraska.New(s.URL, "test", "", "", ""),
+		cache:  sharedReadCache(s.URL, "", "", ""),
+	}
+	var ids []string
+	for _, version := range []string{"1.0.0", "1.1.0", "2.0.0"} {
+		p, err := c.AddPackage(nebraska.FlatcarApplicationID, &nebraska.AddPackageInput{
+			Arch:              nebraska.ArchAMD64,
+			ChannelsBlacklist: []string{},
+			Type:              nebraska.PackageTypeFlatcar,
+			URL:               "https://update.release.flatcar-linux.net/",
+			Version:           version,
+		})
+		assert.NilError(t, err)
+		ids = append(ids, p.ID)
+	}
+
+	list := "GET /api/apps/" + nebraska.FlatcarApplicationID + "/packages"
+	var wg sync.WaitGroup
+	for _, id := range ids {
+		wg.Go(func() {
+			p, err := c.GetPackage(nebraska.FlatcarApplicationID, id)
+			assert.NilError(t, err)
+			assert.Equal(t, p.ID, id)
+		})
+	}
+	wg.Wait()
+	page, err := c.SearchPackages(nebraska.FlatcarApplicationID, "1.")
+	assert.NilError(t, err)
+	assert.Equal(t, page.TotalCount, 2)
+	_, err = c.GetPackage(nebraska.FlatcarApplicationID, "missing")
+	assert.Equal(t, err, nebraska.ErrNotFound)
+	assert.Equal(t, requests[list], 1)
+
+	// Writes drop the cache
+	_, err = c.UpdatePackage(nebraska.FlatcarApplicationID, ids[0], &nebraska.UpdatePackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{},
+		Type:              nebraska.PackageTypeFlatcar,
+		URL:               "https://update.release.flatcar-linux.net/",
+		Version:           "1.0.0",
+		Description:       "updated",
+	})
+	assert.NilError(t, err)
+	p, err := c.GetPackage(nebraska.FlatcarApplicationID, ids[0])
+	assert.NilError(t, err)
+	assert.Equal(t, p.Description, "updated")
+	assert.Equal(t, requests[list], 2)
+	assert.Equal(t, requests["GET /api/apps/"+nebraska.FlatcarApplicationID+"/packages/"+ids[0]], 0)
+}
+
+func TestReadCacheShared(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	defer fake.Close()
+
+	// Provider configurations with different credentials don't share their
+	// reads, but a write through any of them drops the reads of all
+	user := &apiClient{
+		Client: nebraska.New(fake.URL, "test", "user", "pass", ""),
+		cache:  sharedReadCache(fake.URL, "user", "pass", ""),
+	}
+	bearer := &apiClient{
+		Client: nebraska.New(fake.URL, "test", "", "", "token"),
+		cache:  sharedReadCache(fake.URL, "", "", "token"),
+	}
+	uncached := &apiClient{
+		Client: nebraska.New(fake.URL, "test", "", "", ""),
+	}
+	assert.Assert(t, user.cache != bearer.cache)
+	assert.Equal(t, sharedReadCache(fake.URL, "user", "pass", ""), user.cache)
+
+	for _, c := range []*apiClient{user, bearer} {
+		page, err := c.ListChannels(nebraska.FlatcarApplicationID)
+		assert.NilError(t, err)
+		assert.Equal(t, page.TotalCount, 0)
+	}
+	_, err := uncached.AddChannel(nebraska.FlatcarApplicationID, &nebraska.AddChannelInput{
+		Name: "stable",
+		Arch: nebraska.ArchAMD64,
+	})
+	assert.NilError(t, err)
+	for _, c := range []*apiClient{user, bearer} {
+		page, err := c.ListChannels(nebraska.FlatcarApplicationID)
+		assert.NilError(t, err)
+		assert.Equal(t, page.TotalCount, 1)
+	}
+}
+
+func TestReadCacheTruncatedList(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/config":
+			json.NewEncoder(w).Encode(map[string]string{"nebraska_version": nebraskatest.DefaultVersion})
+		case "/api/apps/" + nebraska.FlatcarApplicationID + "/packages":
+			json.NewEncoder(w).Encode(nebraska.PackagePage{
+				Packages:   []nebraska.Package{{ID: "p1", Version: "1.0.0"}},
+				Count:      1,
+				TotalCount: 2,
+			})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer s.Close()
+
+	c := &apiClient{
+		Client: nebraska.New(s.URL, "test", "", "", ""),
+		cache:  newReadCache(),
+	}
+	page, err := c.ListPackages(nebraska.FlatcarApplicationID)
+	assert.NilError(t, err)
+	assert.Equal(t, page.Count, 1)
+	assert.Equal(t, page.TotalCount, 2)
+
+	// A package past the end of the list isn't reported as deleted
+	_, err = c.GetPackage(nebraska.FlatcarApplicationID, "p2")
+	assert.ErrorContains(t, err, "We don't paginate")
+	_, err = c.SearchPackages(nebraska.FlatcarApplicationID, "1.")
+	assert.ErrorContains(t, err, "We don't paginate")
+}
@@ -4,85 +4,166 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/kinvolk/nebraska/backend/pkg/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func dataSourceChannel() *schema.Resource {
-	return &schema.Resource{
-		Description: "A release channel that provides a particular package version.",
-		ReadContext: dataSourceChannelRead,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Name of the channel.",
+var (
+	_ datasource.DataSourceWithConfigure        = &channelDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &channelDataSource{}
+)
+
+// NewChannelDataSource returns the nebraska_channel data source
+func NewChannelDataSource() datasource.DataSource {
+	return &channelDataSource{}
+}
+
+type channelDataSource struct {
+	client *apiClient
+}
+
+type channelDataSourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Arch          types.String `tfsdk:"arch"`
+	ApplicationID types.String `tfsdk:"application_id"`
+	Color         types.String `tfsdk:"color"`
+	CreatedTs     types.String `tfsdk:"created_ts"`
+	PackageID     types.String `tfsdk:"package_id"`
+}
+
+func (d *channelDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_channel"
+}
+
+func (d *channelDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A release channel that provides a particular package version.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the channel. Conflicts with `name` and `arch`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name of the channel. Must be set together with `arch`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"arch": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice([]string{"all", "amd64", "aarch64", "x86"}, false),
-				Description:  "Arch.",
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Arch. Must be set together with `name`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
 			},
-			"application_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				ForceNew:    true,
-				Description: "ID of the application this channel belongs to.",
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application this channel belongs to.",
 			},
-			"color": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "Hex color code of the channel on the UI.",
+			"color": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Hex color code of the channel on the UI.",
 			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp.",
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp.",
 			},
-			"package_id": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "ID of this channel's package.",
+			"package_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of this channel's package.",
 			},
 		},
 	}
 }
 
-func dataSourceChannelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (d *channelDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name")),
+		datasourcevalidator.RequiredTogether(path.MatchRoot("name"), path.MatchRoot("arch")),
+	}
+}
 
-	appID, err := getApplicationID(d, c)
+func (d *channelDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *channelDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config channelDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, d.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't read channel", err.Error())
+		return
+	}
+
+	var channel *nebraska.Channel
+	if id := config.ID.ValueString(); id != "" {
+		channel, err = d.client.WithContext(ctx).GetChannel(appID, id)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				resp.Diagnostics.AddError("Couldn't read channel", fmt.Sprintf("couldn't find channel %s", id))
+				return
+			}
+			resp.Diagnostics.AddError("Couldn't read channel", err.Error())
+			return
+		}
+	} else {
+		channel, err = findChannel(d.client.WithContext(ctx), appID, config.Name.ValueString(), config.Arch.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't read channel", err.Error())
+			return
+		}
 	}
-	d.Set("application_id", appID)
+
+	state := channelDataSourceModel{
+		ID:            types.StringValue(channel.ID),
+		Name:          types.StringValue(channel.Name),
+		Arch:          types.StringValue(channel.Arch.String()),
+		ApplicationID: types.StringValue(appID),
+		Color:         types.StringValue(channel.Color),
+		CreatedTs:     types.StringValue(channel.CreatedTs.String()),
+		PackageID:     types.StringValue(channel.PackageID),
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// findChannel returns the channel in the application with the given name and
+// arch
+func findChannel(c *apiClient, appID, name, arch string) (*nebraska.Channel, error) {
 	channelPage, err := c.ListChannels(appID)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 	if channelPage.Count != channelPage.TotalCount {
-		return diag.FromErr(fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount))
+		return nil, fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
 	}
-	name := d.Get("name").(string)
-	arch := d.Get("arch").(string)
-
-	for _, c := range channelPage.Channels {
-		if c.Name == name && api.Arch(c.Arch).String() == arch {
-			d.SetId(c.Id)
-			d.Set("color", c.Color)
-			d.Set("created_ts", c.CreatedTs.String())
-			d.Set("package_id", c.PackageID)
 
-			return nil
+	for i, c := range channelPage.Channels {
+		if c.Name == name && c.Arch.String() == arch {
+			return &channelPage.Channels[i], nil
 		}
 	}
 
-	return diag.Errorf("couldn't find channel %s (%s)", name, arch)
+	return nil, fmt.Errorf("couldn't find channel %s (%s): %w", name, arch, nebraska.ErrNotFound)
 }
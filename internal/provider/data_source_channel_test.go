@@ -1,30 +1,35 @@
 package provider
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccChannelDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "data.nebraska_channel.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccDataSourceChannel,
+				Config: fmt.Sprintf(testAccDataSourceChannel, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
-					resource.TestCheckResourceAttr(dsn, "name", "test-terraform"),
+					resource.TestCheckResourceAttr(dsn, "name", rName),
 					resource.TestCheckResourceAttr(dsn, "arch", "amd64"),
 					resource.TestCheckResourceAttrSet(dsn, "package_id"),
 					resource.TestCheckResourceAttr(dsn, "color", "#1fbb86"),
 					resource.TestCheckResourceAttrSet(dsn, "created_ts"),
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
+					resource.TestCheckResourceAttrPair("data.nebraska_channel.by_id", "name", dsn, "name"),
+					resource.TestCheckResourceAttrPair("data.nebraska_channel.by_id", "arch", dsn, "arch"),
+					resource.TestCheckResourceAttrPair("data.nebraska_channel.by_id", "package_id", dsn, "package_id"),
 				),
 			},
 		},
@@ -36,13 +41,13 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test" {
-  version = "0.0.0"
+  version = "0.0.0-%[1]s"
   arch    = "amd64"
   url     = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "test-terraform"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test.id
   color      = "#1fbb86"
@@ -52,4 +57,8 @@ data "nebraska_channel" "test" {
  name = nebraska_channel.test.name
  arch = nebraska_channel.test.arch
 }
+
+data "nebraska_channel" "by_id" {
+ id = nebraska_channel.test.id
+}
 `
@@ -0,0 +1,130 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func dataSourceChannels() *schema.Resource {
+	return &schema.Resource{
+		Description: "All the release channels of an application, optionally filtered.",
+		ReadContext: dataSourceChannelsRead,
+		Schema: map[string]*schema.Schema{
+			"application_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the application the channels belong to.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return channels with a name matching this regular expression.",
+			},
+			"arch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(nebraska.ValidArchs, false),
+				Description:  "Only return channels with this arch.",
+			},
+			"channels": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching channels.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the channel.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the channel.",
+						},
+						"arch": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Arch.",
+						},
+						"color": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Hex color code of the channel on the UI.",
+						},
+						"created_ts": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creation timestamp.",
+						},
+						"package_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of this channel's package.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceChannelsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*apiClient).WithContext(ctx)
+
+	appID, err := getApplicationID(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("application_id", appID)
+	channelPage, err := c.ListChannels(appID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if channelPage.Count != channelPage.TotalCount {
+		return diag.FromErr(fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount))
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	arch := d.Get("arch").(string)
+
+	channels := make([]map[string]interface{}, 0, len(channelPage.Channels))
+	for _, c := range channelPage.Channels {
+		if nameRegex != nil && !nameRegex.MatchString(c.Name) {
+			continue
+		}
+		if arch != "" && c.Arch.String() != arch {
+			continue
+		}
+		channels = append(channels, flattenChannel(c))
+	}
+
+	d.SetId(appID)
+	if err := d.Set("channels", channels); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenChannel(c nebraska.Channel) map[string]interface{} {
+	return map[string]interface{}{
+		"id":         c.ID,
+		"name":       c.Name,
+		"arch":       c.Arch.String(),
+		"color":      c.Color,
+		"created_ts": c.CreatedTs.String(),
+		"package_id": c.PackageID,
+	}
+}
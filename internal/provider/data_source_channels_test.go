@@ -0,0 +1,59 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccChannelsDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "data.nebraska_channels.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceChannels, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dsn, "id"),
+					resource.TestCheckResourceAttrSet(dsn, "application_id"),
+					resource.TestCheckResourceAttr(dsn, "channels.#", "1"),
+					resource.TestCheckResourceAttrPair(dsn, "channels.0.id", "nebraska_channel.test", "id"),
+					resource.TestCheckResourceAttr(dsn, "channels.0.name", rName),
+					resource.TestCheckResourceAttr(dsn, "channels.0.arch", "amd64"),
+					resource.TestCheckResourceAttr(dsn, "channels.0.color", "#1fbb86"),
+					resource.TestCheckResourceAttrSet(dsn, "channels.0.package_id"),
+					resource.TestCheckResourceAttrSet(dsn, "channels.0.created_ts"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceChannels = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version = "0.0.0-%[1]s"
+  arch    = "amd64"
+  url     = "http://fake-address/"
+}
+
+resource "nebraska_channel" "test" {
+  name       = "%[1]s"
+  arch       = "amd64"
+  package_id = nebraska_package.test.id
+  color      = "#1fbb86"
+}
+
+data "nebraska_channels" "test" {
+ name_regex = "^${nebraska_channel.test.name}$"
+ arch       = nebraska_channel.test.arch
+}
+`
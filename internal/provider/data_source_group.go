@@ -4,128 +4,220 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func dataSourceGroup() *schema.Resource {
-	return &schema.Resource{
-		Description: "A group provides a particular release channel to machines and controls various options that manage the update procedure.",
-		ReadContext: dataSourceGroupRead,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Name of the group.",
+var (
+	_ datasource.DataSourceWithConfigure        = &groupDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &groupDataSource{}
+)
+
+// NewGroupDataSource returns the nebraska_group data source
+func NewGroupDataSource() datasource.DataSource {
+	return &groupDataSource{}
+}
+
+type groupDataSource struct {
+	client *apiClient
+}
+
+type groupDataSourceModel struct {
+	ID                        types.String `tfsdk:"id"`
+	Name                      types.String `tfsdk:"name"`
+	ApplicationID             types.String `tfsdk:"application_id"`
+	Description               types.String `tfsdk:"description"`
+	CreatedTs                 types.String `tfsdk:"created_ts"`
+	RolloutInProgress         types.Bool   `tfsdk:"rollout_in_progress"`
+	ChannelID                 types.String `tfsdk:"channel_id"`
+	PolicyUpdatesEnabled      types.Bool   `tfsdk:"policy_updates_enabled"`
+	PolicySafeMode            types.Bool   `tfsdk:"policy_safe_mode"`
+	PolicyOfficeHours         types.Bool   `tfsdk:"policy_office_hours"`
+	PolicyTimezone            types.String `tfsdk:"policy_timezone"`
+	PolicyPeriodInterval      types.String `tfsdk:"policy_period_interval"`
+	PolicyMaxUpdatesPerPeriod types.Int64  `tfsdk:"policy_max_updates_per_period"`
+	PolicyUpdateTimeout       types.String `tfsdk:"policy_update_timeout"`
+	Track                     types.String `tfsdk:"track"`
+}
+
+func (d *groupDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group"
+}
+
+func (d *groupDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A group provides a particular release channel to machines and controls various options that manage the update procedure.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the group. Conflicts with `name` and `track`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"application_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Computed:    true,
-				Description: "ID of the application this group belongs to.",
+			"name": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Name of the group. Conflicts with `id` and `track`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"description": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "A description of the group.",
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application this group belongs to.",
 			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp.",
+			"description": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "A description of the group.",
 			},
-			"rollout_in_progress": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Indicates whether a rollout is currently in progress for this group.",
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp.",
 			},
-			"channel_id": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The channel this group provides.",
+			"rollout_in_progress": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Indicates whether a rollout is currently in progress for this group.",
 			},
-			"policy_updates_enabled": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Are updates enabled?",
+			"channel_id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The channel this group provides.",
 			},
-			"policy_safe_mode": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Safe mode will only update 1 instance at a time, and stop if an update fails.",
+			"policy_updates_enabled": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Are updates enabled?",
 			},
-			"policy_office_hours": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Only update between 9am and 5pm.",
+			"policy_safe_mode": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Safe mode will only update 1 instance at a time, and stop if an update fails.",
 			},
-			"policy_timezone": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Timezone used to inform `policy_office_hours`.",
+			"policy_office_hours": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Only update between 9am and 5pm.",
 			},
-			"policy_period_interval": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Period used in combination with `policy_max_updates_per_period`.",
+			"policy_timezone": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Timezone used to inform `policy_office_hours`.",
 			},
-			"policy_max_updates_per_period": {
-				Type:        schema.TypeInt,
-				Computed:    true,
-				Description: "The maximum number of updates that can be performed within the `policy_period_interval`.",
+			"policy_period_interval": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Period used in combination with `policy_max_updates_per_period`.",
 			},
-			"policy_update_timeout": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Timeout for updates.",
+			"policy_max_updates_per_period": schema.Int64Attribute{
+				Computed:            true,
+				MarkdownDescription: "The maximum number of updates that can be performed within the `policy_period_interval`.",
 			},
-			"track": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Identifier for clients.",
+			"policy_update_timeout": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Timeout for updates.",
+			},
+			"track": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Identifier for clients. Conflicts with `id` and `name`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
 		},
 	}
 }
 
-func dataSourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (d *groupDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("name"), path.MatchRoot("track")),
+	}
+}
+
+func (d *groupDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
 
-	appID, err := getApplicationID(d, c)
+func (d *groupDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config groupDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, d.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't read group", err.Error())
+		return
+	}
+
+	var group *nebraska.Group
+	if id := config.ID.ValueString(); id != "" {
+		group, err = d.client.WithContext(ctx).GetGroup(appID, id)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				resp.Diagnostics.AddError("Couldn't read group", fmt.Sprintf("couldn't find group %s", id))
+				return
+			}
+			resp.Diagnostics.AddError("Couldn't read group", err.Error())
+			return
+		}
+	} else {
+		group, err = findGroup(d.client.WithContext(ctx), appID, config.Name.ValueString(), config.Track.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't read group", err.Error())
+			return
+		}
+	}
+
+	state := groupDataSourceModel{
+		ID:                        types.StringValue(group.ID),
+		Name:                      types.StringValue(group.Name),
+		ApplicationID:             types.StringValue(appID),
+		Description:               types.StringValue(group.Description),
+		CreatedTs:                 types.StringValue(group.CreatedTs.String()),
+		RolloutInProgress:         types.BoolValue(group.RolloutInProgress),
+		ChannelID:                 types.StringValue(group.ChannelID),
+		PolicyUpdatesEnabled:      types.BoolValue(group.PolicyUpdatesEnabled),
+		PolicySafeMode:            types.BoolValue(group.PolicySafeMode),
+		PolicyOfficeHours:         types.BoolValue(group.PolicyOfficeHours),
+		PolicyTimezone:            types.StringValue(group.PolicyTimezone),
+		PolicyPeriodInterval:      types.StringValue(group.PolicyPeriodInterval),
+		PolicyMaxUpdatesPerPeriod: types.Int64Value(int64(group.PolicyMaxUpdatesPerPeriod)),
+		PolicyUpdateTimeout:       types.StringValue(group.PolicyUpdateTimeout),
+		Track:                     types.StringValue(group.Track),
 	}
-	d.Set("application_id", appID)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// findGroup returns the single group in the application that matches the
+// given name or track, whichever is set
+func findGroup(c *apiClient, appID, name, track string) (*nebraska.Group, error) {
 	groupPage, err := c.ListGroups(appID)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 	if groupPage.Count != groupPage.TotalCount {
-		return diag.FromErr(fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount))
+		return nil, fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount)
 	}
-	name := d.Get("name").(string)
-	for _, g := range groupPage.Groups {
-		if g.Name == name {
-			d.SetId(g.Id)
-			d.Set("description", g.Description)
-			d.Set("created_ts", g.CreatedTs.String())
-			d.Set("rollout_in_progress", g.RolloutInProgress)
-			d.Set("channel_id", g.ChannelID)
-			d.Set("policy_updates_enabled", g.PolicyUpdatesEnabled)
-			d.Set("policy_safe_mode", g.PolicySafeMode)
-			d.Set("policy_office_hours", g.PolicyOfficeHours)
-			d.Set("policy_timezone", g.PolicyTimezone)
-			d.Set("policy_period_interval", g.PolicyPeriodInterval)
-			d.Set("policy_max_updates_per_period", g.PolicyMaxUpdatesPerPeriod)
-			d.Set("policy_update_timeout", g.PolicyUpdateTimeout)
-			d.Set("track", g.Track)
-
-			return nil
+
+	var found *nebraska.Group
+	for i, g := range groupPage.Groups {
+		if (name != "" && g.Name != name) || (track != "" && g.Track != track) {
+			continue
+		}
+		if found != nil {
+			return nil, fmt.Errorf("found more than one group with name %q and track %q", name, track)
 		}
+		found = &groupPage.Groups[i]
+	}
+	if found == nil {
+		return nil, fmt.Errorf("couldn't find group with name %q and track %q: %w", name, track, nebraska.ErrNotFound)
 	}
 
-	return diag.Errorf("couldn't find group")
+	return found, nil
 }
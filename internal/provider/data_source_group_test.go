@@ -1,29 +1,31 @@
 package provider
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccGroupDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "data.nebraska_group.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccDataSourceGroup,
+				Config: fmt.Sprintf(testAccDataSourceGroup, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
 					resource.TestCheckResourceAttrSet(dsn, "rollout_in_progress"),
 					resource.TestCheckResourceAttrSet(dsn, "channel_id"),
 					resource.TestCheckResourceAttrSet(dsn, "created_ts"),
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
-					resource.TestCheckResourceAttr(dsn, "name", "terraform-test"),
+					resource.TestCheckResourceAttr(dsn, "name", rName),
 					resource.TestCheckResourceAttr(dsn, "description", "Test description"),
 					resource.TestCheckResourceAttr(dsn, "policy_updates_enabled", "false"),
 					resource.TestCheckResourceAttr(dsn, "policy_safe_mode", "true"),
@@ -32,6 +34,10 @@ func TestAccGroupDataSource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(dsn, "policy_period_interval", "10 minutes"),
 					resource.TestCheckResourceAttr(dsn, "policy_max_updates_per_period", "10"),
 					resource.TestCheckResourceAttr(dsn, "policy_update_timeout", "35 minutes"),
+					resource.TestCheckResourceAttrPair("data.nebraska_group.by_id", "name", dsn, "name"),
+					resource.TestCheckResourceAttrPair("data.nebraska_group.by_id", "track", dsn, "track"),
+					resource.TestCheckResourceAttrPair("data.nebraska_group.by_track", "id", dsn, "id"),
+					resource.TestCheckResourceAttrPair("data.nebraska_group.by_track", "name", dsn, "name"),
 				),
 			},
 		},
@@ -43,20 +49,20 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test" {
-  version = "0.0.0"
+  version = "0.0.0-%[1]s"
   arch    = "amd64"
   url     = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "terraform-test"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test.id
 }
 
 resource "nebraska_group" "test" {
-  name                           = "terraform-test"
-  track                          = "terraform-test"
+  name                           = "%[1]s"
+  track                          = "%[1]s"
   description                    = "Test description"
   channel_id                     = nebraska_channel.test.id
   policy_updates_enabled         = "false"
@@ -71,4 +77,12 @@ resource "nebraska_group" "test" {
 data "nebraska_group" "test" {
  name = nebraska_group.test.name
 }
+
+data "nebraska_group" "by_id" {
+ id = nebraska_group.test.id
+}
+
+data "nebraska_group" "by_track" {
+ track = nebraska_group.test.track
+}
 `
@@ -0,0 +1,177 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func dataSourceGroups() *schema.Resource {
+	return &schema.Resource{
+		Description: "All the groups of an application, optionally filtered.",
+		ReadContext: dataSourceGroupsRead,
+		Schema: map[string]*schema.Schema{
+			"application_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the application the groups belong to.",
+			},
+			"name_regex": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringIsValidRegExp,
+				Description:  "Only return groups with a name matching this regular expression.",
+			},
+			"channel_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return groups that provide this channel.",
+			},
+			"groups": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching groups.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the group.",
+						},
+						"name": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Name of the group.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A description of the group.",
+						},
+						"created_ts": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creation timestamp.",
+						},
+						"rollout_in_progress": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Indicates whether a rollout is currently in progress for this group.",
+						},
+						"channel_id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The channel this group provides.",
+						},
+						"policy_updates_enabled": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Are updates enabled?",
+						},
+						"policy_safe_mode": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Safe mode will only update 1 instance at a time, and stop if an update fails.",
+						},
+						"policy_office_hours": {
+							Type:        schema.TypeBool,
+							Computed:    true,
+							Description: "Only update between 9am and 5pm.",
+						},
+						"policy_timezone": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timezone used to inform `policy_office_hours`.",
+						},
+						"policy_period_interval": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Period used in combination with `policy_max_updates_per_period`.",
+						},
+						"policy_max_updates_per_period": {
+							Type:        schema.TypeInt,
+							Computed:    true,
+							Description: "The maximum number of updates that can be performed within the `policy_period_interval`.",
+						},
+						"policy_update_timeout": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Timeout for updates.",
+						},
+						"track": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Identifier for clients.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourceGroupsRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*apiClient).WithContext(ctx)
+
+	appID, err := getApplicationID(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("application_id", appID)
+	groupPage, err := c.ListGroups(appID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if groupPage.Count != groupPage.TotalCount {
+		return diag.FromErr(fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount))
+	}
+
+	var nameRegex *regexp.Regexp
+	if v, ok := d.GetOk("name_regex"); ok {
+		nameRegex = regexp.MustCompile(v.(string))
+	}
+	channelID := d.Get("channel_id").(string)
+
+	groups := make([]map[string]interface{}, 0, len(groupPage.Groups))
+	for _, g := range groupPage.Groups {
+		if nameRegex != nil && !nameRegex.MatchString(g.Name) {
+			continue
+		}
+		if channelID != "" && g.ChannelID != channelID {
+			continue
+		}
+		groups = append(groups, flattenGroup(g))
+	}
+
+	d.SetId(appID)
+	if err := d.Set("groups", groups); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenGroup(g nebraska.Group) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                            g.ID,
+		"name":                          g.Name,
+		"description":                   g.Description,
+		"created_ts":                    g.CreatedTs.String(),
+		"rollout_in_progress":           g.RolloutInProgress,
+		"channel_id":                    g.ChannelID,
+		"policy_updates_enabled":        g.PolicyUpdatesEnabled,
+		"policy_safe_mode":              g.PolicySafeMode,
+		"policy_office_hours":           g.PolicyOfficeHours,
+		"policy_timezone":               g.PolicyTimezone,
+		"policy_period_interval":        g.PolicyPeriodInterval,
+		"policy_max_updates_per_period": g.PolicyMaxUpdatesPerPeriod,
+		"policy_update_timeout":         g.PolicyUpdateTimeout,
+		"track":                         g.Track,
+	}
+}
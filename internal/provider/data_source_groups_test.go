@@ -0,0 +1,67 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccGroupsDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "data.nebraska_groups.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceGroups, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dsn, "id"),
+					resource.TestCheckResourceAttrSet(dsn, "application_id"),
+					resource.TestCheckResourceAttr(dsn, "groups.#", "1"),
+					resource.TestCheckResourceAttrPair(dsn, "groups.0.id", "nebraska_group.test", "id"),
+					resource.TestCheckResourceAttrPair(dsn, "groups.0.channel_id", "nebraska_channel.test", "id"),
+					resource.TestCheckResourceAttr(dsn, "groups.0.name", rName),
+					resource.TestCheckResourceAttr(dsn, "groups.0.track", rName),
+					resource.TestCheckResourceAttr(dsn, "groups.0.description", "Test description"),
+					resource.TestCheckResourceAttr(dsn, "groups.0.policy_updates_enabled", "false"),
+					resource.TestCheckResourceAttrSet(dsn, "groups.0.created_ts"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceGroups = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version = "0.0.0-%[1]s"
+  arch    = "amd64"
+  url     = "http://fake-address/"
+}
+
+resource "nebraska_channel" "test" {
+  name       = "%[1]s"
+  arch       = "amd64"
+  package_id = nebraska_package.test.id
+}
+
+resource "nebraska_group" "test" {
+  name                   = "%[1]s"
+  track                  = "%[1]s"
+  description            = "Test description"
+  channel_id             = nebraska_channel.test.id
+  policy_updates_enabled = "false"
+}
+
+data "nebraska_groups" "test" {
+ name_regex = "^${nebraska_group.test.name}$"
+ channel_id = nebraska_group.test.channel_id
+}
+`
@@ -4,173 +4,221 @@ import (
 	"context"
 	"fmt"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/kinvolk/nebraska/backend/pkg/api"
+	"github.com/hashicorp/terraform-plugin-framework-validators/datasourcevalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func dataSourcePackage() *schema.Resource {
-	return &schema.Resource{
-		Description: "A versioned package of the application.",
-		ReadContext: dataSourcePackageRead,
-		Schema: map[string]*schema.Schema{
-			"version": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Package version.",
+// flatcarActionAttrTypes are the attributes of a flatcar_action
+var flatcarActionAttrTypes = map[string]attr.Type{
+	"id":                      types.StringType,
+	"event":                   types.StringType,
+	"chromeos_version":        types.StringType,
+	"sha256":                  types.StringType,
+	"needs_admin":             types.BoolType,
+	"is_delta":                types.BoolType,
+	"disable_payload_backoff": types.BoolType,
+	"metadata_signature_rsa":  types.StringType,
+	"metadata_size":           types.StringType,
+	"deadline":                types.StringType,
+	"created_ts":              types.StringType,
+}
+
+var (
+	_ datasource.DataSourceWithConfigure        = &packageDataSource{}
+	_ datasource.DataSourceWithConfigValidators = &packageDataSource{}
+)
+
+// NewPackageDataSource returns the nebraska_package data source
+func NewPackageDataSource() datasource.DataSource {
+	return &packageDataSource{}
+}
+
+type packageDataSource struct {
+	client *apiClient
+}
+
+type packageDataSourceModel struct {
+	ID                types.String         `tfsdk:"id"`
+	Version           types.String         `tfsdk:"version"`
+	Arch              types.String         `tfsdk:"arch"`
+	ApplicationID     types.String         `tfsdk:"application_id"`
+	Type              types.String         `tfsdk:"type"`
+	URL               types.String         `tfsdk:"url"`
+	Filename          types.String         `tfsdk:"filename"`
+	Description       types.String         `tfsdk:"description"`
+	Size              types.String         `tfsdk:"size"`
+	Hash              types.String         `tfsdk:"hash"`
+	CreatedTs         types.String         `tfsdk:"created_ts"`
+	FlatcarAction     []flatcarActionModel `tfsdk:"flatcar_action"`
+	ChannelsBlacklist []string             `tfsdk:"channels_blacklist"`
+}
+
+func (d *packageDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package"
+}
+
+func (d *packageDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A versioned package of the application.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the package. Conflicts with `version` and `arch`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
 			},
-			"arch": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice([]string{"all", "amd64", "aarch64", "x86"}, false),
-				Description:  "Package arch.",
+			"version": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Package version. Must be set together with `arch`.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Package arch. Must be set together with `version`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
 			},
-			"application_id": {
-				Type:     schema.TypeString,
+			"application_id": schema.StringAttribute{
 				Optional: true,
-				ForceNew: true,
 				Computed: true,
 			},
-			"type": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Type of package.",
+			"type": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Type of package.",
 			},
-			"url": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "URL where the package is available.",
+			"url": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "URL where the package is available.",
 			},
-			"filename": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The filename of the package.",
+			"filename": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The filename of the package.",
 			},
-			"description": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "A description of the package.",
+			"description": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "A description of the package.",
 			},
-			"size": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "The size, in bytes.",
+			"size": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The size, in bytes.",
 			},
-			"hash": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "A base64 encoded sha1 hash of the package digest.",
+			"hash": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "A base64 encoded sha1 hash of the package digest.",
 			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp.",
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp.",
 			},
-			"flatcar_action": {
-				Type:        schema.TypeList,
-				Computed:    true,
-				Description: "A Flatcar specific Omaha action.",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"event": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"chromeos_version": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"sha256": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"needs_admin": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"is_delta": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"disable_payload_backoff": {
-							Type:     schema.TypeBool,
-							Computed: true,
-						},
-						"metadata_signature_rsa": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"metadata_size": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"deadline": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-						"created_ts": {
-							Type:     schema.TypeString,
-							Computed: true,
-						},
-					},
-				},
+			"flatcar_action": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.ObjectType{AttrTypes: flatcarActionAttrTypes},
+				MarkdownDescription: "A Flatcar specific Omaha action.",
 			},
-			"channels_blacklist": {
-				Type:     schema.TypeList,
-				Computed: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-				Description: "A list of channels (by id) that cannot point to this package.",
+			"channels_blacklist": schema.ListAttribute{
+				Computed:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A list of channels (by id) that cannot point to this package.",
 			},
 		},
 	}
 }
 
-func dataSourcePackageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (d *packageDataSource) ConfigValidators(ctx context.Context) []datasource.ConfigValidator {
+	return []datasource.ConfigValidator{
+		datasourcevalidator.ExactlyOneOf(path.MatchRoot("id"), path.MatchRoot("version")),
+		datasourcevalidator.RequiredTogether(path.MatchRoot("version"), path.MatchRoot("arch")),
+	}
+}
+
+func (d *packageDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *packageDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var config packageDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	appID, err := applicationID(config.ApplicationID, d.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't read package", err.Error())
+		return
 	}
-	d.Set("application_id", appID)
 
-	version := d.Get("version").(string)
-	arch := d.Get("arch").(string)
+	var p *nebraska.Package
+	if id := config.ID.ValueString(); id != "" {
+		p, err = d.client.WithContext(ctx).GetPackage(appID, id)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				resp.Diagnostics.AddError("Couldn't read package", fmt.Sprintf("couldn't find package %s", id))
+				return
+			}
+			resp.Diagnostics.AddError("Couldn't read package", err.Error())
+			return
+		}
+	} else {
+		p, err = findPackage(d.client.WithContext(ctx), appID, config.Version.ValueString(), config.Arch.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't read package", err.Error())
+			return
+		}
+	}
+
+	state := packageDataSourceModel{
+		ID:                types.StringValue(p.ID),
+		Version:           types.StringValue(p.Version),
+		Arch:              types.StringValue(p.Arch.String()),
+		ApplicationID:     types.StringValue(appID),
+		Type:              types.StringValue(p.Type.String()),
+		URL:               types.StringValue(p.URL),
+		Filename:          types.StringValue(p.Filename),
+		Description:       types.StringValue(p.Description),
+		Size:              types.StringValue(p.Size),
+		Hash:              types.StringValue(p.Hash),
+		CreatedTs:         types.StringValue(p.CreatedTs.String()),
+		FlatcarAction:     []flatcarActionModel{},
+		ChannelsBlacklist: append([]string{}, p.ChannelsBlacklist...),
+	}
+	if p.FlatcarAction != nil {
+		state.FlatcarAction = append(state.FlatcarAction, newFlatcarActionModel(*p.FlatcarAction))
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
 
+// findPackage returns the package in the application with the given version
+// and arch
+func findPackage(c *apiClient, appID, version, arch string) (*nebraska.Package, error) {
 	packagePage, err := c.SearchPackages(appID, version)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
 	}
 	if packagePage.Count != packagePage.TotalCount {
-		return diag.FromErr(fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount))
+		return nil, fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount)
 	}
 
-	for _, p := range packagePage.Packages {
-		if p.Version == version && api.Arch(p.Arch).String() == arch {
-			d.SetId(p.Id)
-			d.Set("type", nebraska.PackageType(p.Type).String())
-			d.Set("url", p.Url)
-			d.Set("filename", p.Filename)
-			d.Set("description", p.Description)
-			d.Set("size", p.Size)
-			d.Set("hash", p.Hash)
-			d.Set("created_ts", p.CreatedTs.String())
-			d.Set("channels_blacklist", p.ChannelsBlacklist)
-			d.Set("flatcar_action", flattenFlatcarAction(p.FlatcarAction))
-
-			return nil
+	for i, p := range packagePage.Packages {
+		if p.Version == version && p.Arch.String() == arch {
+			return &packagePage.Packages[i], nil
 		}
 	}
 
-	return diag.Errorf("couldn't find package")
+	return nil, fmt.Errorf("couldn't find package %s (%s)", version, arch)
 }
@@ -1,27 +1,29 @@
 package provider
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
 )
 
 func TestAccPackageDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "data.nebraska_package.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccDataSourcePackage,
+				Config: fmt.Sprintf(testAccDataSourcePackage, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
 					resource.TestCheckResourceAttrSet(dsn, "created_ts"),
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
-					resource.TestCheckResourceAttr(dsn, "version", "0.0.0"),
+					resource.TestCheckResourceAttr(dsn, "version", "0.0.0-"+rName),
 					resource.TestCheckResourceAttr(dsn, "arch", "amd64"),
 					resource.TestCheckResourceAttr(dsn, "type", "flatcar"),
 					resource.TestCheckResourceAttr(dsn, "url", "http://fake-address/"),
@@ -29,6 +31,9 @@ func TestAccPackageDataSource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(dsn, "description", "Test package"),
 					resource.TestCheckResourceAttr(dsn, "size", "465881871"),
 					resource.TestCheckResourceAttr(dsn, "hash", "r3nufcxgMTZaxYEqL+x2zIoeClk="),
+					resource.TestCheckResourceAttrPair("data.nebraska_package.by_id", "version", dsn, "version"),
+					resource.TestCheckResourceAttrPair("data.nebraska_package.by_id", "arch", dsn, "arch"),
+					resource.TestCheckResourceAttrPair("data.nebraska_package.by_id", "url", dsn, "url"),
 				),
 			},
 		},
@@ -40,19 +45,19 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test_0" {
-  version  = "0.1.0"
+  version  = "0.1.0-%[1]s"
   arch     = "amd64"
   url      = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "terraform-test"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test_0.id
 }
 
 resource "nebraska_package" "test" {
-  version  = "0.0.0"
+  version  = "0.0.0-%[1]s"
   arch     = "amd64"
   url      = "http://fake-address/"
   type     = "flatcar"
@@ -69,4 +74,8 @@ data "nebraska_package" "test" {
  version = nebraska_package.test.version
  arch    = nebraska_package.test.arch
 }
+
+data "nebraska_package" "by_id" {
+ id = nebraska_package.test.id
+}
 `
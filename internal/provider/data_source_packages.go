@@ -0,0 +1,246 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func dataSourcePackages() *schema.Resource {
+	return &schema.Resource{
+		Description: "All the versioned packages of an application, optionally filtered.",
+		ReadContext: dataSourcePackagesRead,
+		Schema: map[string]*schema.Schema{
+			"application_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the application the packages belong to.",
+			},
+			"arch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(nebraska.ValidArchs, false),
+				Description:  "Only return packages with this arch.",
+			},
+			"type": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				ValidateFunc: validation.StringInSlice(nebraska.ValidPackageTypes, false),
+				Description:  "Only return packages of this type.",
+			},
+			"version_prefix": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Description: "Only return packages with a version starting with this prefix.",
+			},
+			"packages": {
+				Type:        schema.TypeList,
+				Computed:    true,
+				Description: "The matching packages.",
+				Elem: &schema.Resource{
+					Schema: map[string]*schema.Schema{
+						"id": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "ID of the package.",
+						},
+						"version": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Package version.",
+						},
+						"arch": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Package arch.",
+						},
+						"type": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Type of package.",
+						},
+						"url": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "URL where the package is available.",
+						},
+						"filename": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The filename of the package.",
+						},
+						"description": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A description of the package.",
+						},
+						"size": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "The size, in bytes.",
+						},
+						"hash": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "A base64 encoded sha1 hash of the package digest.",
+						},
+						"created_ts": {
+							Type:        schema.TypeString,
+							Computed:    true,
+							Description: "Creation timestamp.",
+						},
+						"flatcar_action": {
+							Type:        schema.TypeList,
+							Computed:    true,
+							Description: "A Flatcar specific Omaha action.",
+							Elem: &schema.Resource{
+								Schema: map[string]*schema.Schema{
+									"id": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"event": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"chromeos_version": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"sha256": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"needs_admin": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"is_delta": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"disable_payload_backoff": {
+										Type:     schema.TypeBool,
+										Computed: true,
+									},
+									"metadata_signature_rsa": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"metadata_size": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"deadline": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+									"created_ts": {
+										Type:     schema.TypeString,
+										Computed: true,
+									},
+								},
+							},
+						},
+						"channels_blacklist": {
+							Type:     schema.TypeList,
+							Computed: true,
+							Elem: &schema.Schema{
+								Type: schema.TypeString,
+							},
+							Description: "A list of channels (by id) that cannot point to this package.",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func dataSourcePackagesRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*apiClient).WithContext(ctx)
+
+	appID, err := getApplicationID(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("application_id", appID)
+
+	packagePage, err := c.ListPackages(appID)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	if packagePage.Count != packagePage.TotalCount {
+		return diag.FromErr(fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount))
+	}
+
+	arch := d.Get("arch").(string)
+	pkgType := d.Get("type").(string)
+	versionPrefix := d.Get("version_prefix").(string)
+
+	packages := make([]map[string]interface{}, 0, len(packagePage.Packages))
+	for _, p := range packagePage.Packages {
+		if arch != "" && p.Arch.String() != arch {
+			continue
+		}
+		if pkgType != "" && p.Type.String() != pkgType {
+			continue
+		}
+		if !strings.HasPrefix(p.Version, versionPrefix) {
+			continue
+		}
+		packages = append(packages, flattenPackage(p))
+	}
+
+	d.SetId(appID)
+	if err := d.Set("packages", packages); err != nil {
+		return diag.FromErr(err)
+	}
+
+	return nil
+}
+
+func flattenPackage(p nebraska.Package) map[string]interface{} {
+	return map[string]interface{}{
+		"id":                 p.ID,
+		"version":            p.Version,
+		"arch":               p.Arch.String(),
+		"type":               p.Type.String(),
+		"url":                p.URL,
+		"filename":           p.Filename,
+		"description":        p.Description,
+		"size":               p.Size,
+		"hash":               p.Hash,
+		"created_ts":         p.CreatedTs.String(),
+		"flatcar_action":     flattenFlatcarAction(p.FlatcarAction),
+		"channels_blacklist": p.ChannelsBlacklist,
+	}
+}
+
+func flattenFlatcarAction(action *nebraska.FlatcarAction) []map[string]interface{} {
+	if action == nil {
+		return []map[string]interface{}{}
+	}
+
+	return []map[string]interface{}{
+		{
+			"id":                      action.ID,
+			"event":                   action.Event,
+			"chromeos_version":        action.ChromeOSVersion,
+			"sha256":                  action.Sha256,
+			"needs_admin":             action.NeedsAdmin,
+			"is_delta":                action.IsDelta,
+			"disable_payload_backoff": action.DisablePayloadBackoff,
+			"metadata_signature_rsa":  action.MetadataSignatureRsa,
+			"metadata_size":           action.MetadataSize,
+			"deadline":                action.Deadline,
+			"created_ts":              action.CreatedTs.String(),
+		},
+	}
+}
@@ -0,0 +1,63 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccPackagesDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "data.nebraska_packages.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourcePackages, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dsn, "id"),
+					resource.TestCheckResourceAttrSet(dsn, "application_id"),
+					resource.TestCheckResourceAttr(dsn, "packages.#", "1"),
+					resource.TestCheckResourceAttrPair(dsn, "packages.0.id", "nebraska_package.test", "id"),
+					resource.TestCheckResourceAttr(dsn, "packages.0.version", "0.0.42-"+rName),
+					resource.TestCheckResourceAttr(dsn, "packages.0.arch", "aarch64"),
+					resource.TestCheckResourceAttr(dsn, "packages.0.type", "flatcar"),
+					resource.TestCheckResourceAttr(dsn, "packages.0.url", "http://fake-address/"),
+					resource.TestCheckResourceAttr(dsn, "packages.0.filename", "test.tgz"),
+					resource.TestCheckResourceAttrSet(dsn, "packages.0.created_ts"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourcePackages = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version  = "0.0.42-%[1]s"
+  arch     = "aarch64"
+  url      = "http://fake-address/"
+  filename = "test.tgz"
+}
+
+resource "nebraska_package" "other" {
+  version  = "0.0.42-%[1]s"
+  arch     = "amd64"
+  url      = "http://fake-address/"
+}
+
+data "nebraska_packages" "test" {
+ arch           = nebraska_package.test.arch
+ type           = "flatcar"
+ version_prefix = "0.0.42-%[1]s"
+
+ depends_on = [nebraska_package.other]
+}
+`
@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+const (
+	// defaultUpdateCheckMachineID is the machine id used for update checks
+	// when one isn't configured. Nebraska leaves instances with ids in this
+	// format out of its statistics.
+	defaultUpdateCheckMachineID = "{9f1b5a34-5d0e-4c1a-8f76-7e3c2b1d0a5e}"
+)
+
+func dataSourceUpdateCheck() *schema.Resource {
+	return &schema.Resource{
+		Description: "Performs an Omaha update check against Nebraska's update endpoint, as a machine on a particular track would, and returns the update it is offered. Note that Nebraska registers the machine as an instance of the group it resolves the track to.",
+		ReadContext: dataSourceUpdateCheckRead,
+		Schema: map[string]*schema.Schema{
+			"track": {
+				Type:         schema.TypeString,
+				Required:     true,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Track of the group the machine belongs to.",
+			},
+			"version": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "0.0.0",
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Version the machine is currently running.",
+			},
+			"arch": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      "amd64",
+				ValidateFunc: validation.StringInSlice([]string{"amd64", "aarch64", "x86"}, false),
+				Description:  "Arch of the machine.",
+			},
+			"machine_id": {
+				Type:         schema.TypeString,
+				Optional:     true,
+				Default:      defaultUpdateCheckMachineID,
+				ValidateFunc: validation.StringIsNotEmpty,
+				Description:  "Machine id reported to Nebraska.",
+			},
+			"application_id": {
+				Type:        schema.TypeString,
+				Optional:    true,
+				Computed:    true,
+				Description: "ID of the application to check for updates.",
+			},
+			"update_available": {
+				Type:        schema.TypeBool,
+				Computed:    true,
+				Description: "Indicates whether an update is offered.",
+			},
+			"status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the update check: `ok` when an update is offered, `noupdate` when there isn't one or an error code otherwise.",
+			},
+			"app_status": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Status of the application in the response. Anything other than `ok` is the reason Nebraska refused the update check, e.g `error-updatesDisabled`.",
+			},
+			"target_version": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "Version that is offered.",
+			},
+			"payload_url": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "URL of the offered payload.",
+			},
+			"hash": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A base64 encoded sha1 hash of the offered payload.",
+			},
+			"sha256": {
+				Type:        schema.TypeString,
+				Computed:    true,
+				Description: "A base64 encoded sha256 hash of the offered payload.",
+			},
+			"size": {
+				Type:        schema.TypeInt,
+				Computed:    true,
+				Description: "The size of the offered payload, in bytes.",
+			},
+		},
+	}
+}
+
+func dataSourceUpdateCheckRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
+	c := meta.(*apiClient).WithContext(ctx)
+
+	appID, err := getApplicationID(d, c)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+	d.Set("application_id", appID)
+
+	input := &nebraska.UpdateCheckInput{
+		AppID:     appID,
+		Track:     d.Get("track").(string),
+		Version:   d.Get("version").(string),
+		Arch:      d.Get("arch").(string),
+		MachineID: d.Get("machine_id").(string),
+	}
+
+	check, err := c.UpdateCheck(input)
+	if err != nil {
+		return diag.FromErr(err)
+	}
+
+	d.SetId(fmt.Sprintf("%s/%s/%s", appID, input.Track, input.MachineID))
+	d.Set("update_available", check.UpdateAvailable)
+	d.Set("status", check.Status)
+	d.Set("app_status", check.AppStatus)
+	d.Set("target_version", check.Version)
+	d.Set("payload_url", check.URL)
+	d.Set("hash", check.Hash)
+	d.Set("sha256", check.SHA256)
+	d.Set("size", int(check.Size))
+
+	return nil
+}
@@ -0,0 +1,68 @@
+package provider
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestAccUpdateCheckDataSource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "data.nebraska_update_check.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccDataSourceUpdateCheck, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dsn, "id"),
+					resource.TestCheckResourceAttrSet(dsn, "application_id"),
+					resource.TestCheckResourceAttr(dsn, "update_available", "true"),
+					resource.TestCheckResourceAttr(dsn, "status", "ok"),
+					resource.TestCheckResourceAttr(dsn, "app_status", "ok"),
+					resource.TestCheckResourceAttr(dsn, "target_version", "0.0.1-"+rName),
+					resource.TestCheckResourceAttr(dsn, "payload_url", "http://fake-address/update.gz"),
+					resource.TestCheckResourceAttr(dsn, "hash", "r3nufcxgMTZaxYEqL+x2zIoeClk="),
+					resource.TestCheckResourceAttr(dsn, "size", "465881871"),
+				),
+			},
+		},
+	})
+}
+
+const testAccDataSourceUpdateCheck = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version  = "0.0.1-%[1]s"
+  arch     = "amd64"
+  url      = "http://fake-address/"
+  filename = "update.gz"
+  size     = "465881871"
+  hash     = "r3nufcxgMTZaxYEqL+x2zIoeClk="
+}
+
+resource "nebraska_channel" "test" {
+  name       = "%[1]s"
+  arch       = "amd64"
+  package_id = nebraska_package.test.id
+}
+
+resource "nebraska_group" "test" {
+  name       = "%[1]s"
+  track      = "%[1]s"
+  channel_id = nebraska_channel.test.id
+}
+
+data "nebraska_update_check" "test" {
+  track   = nebraska_group.test.track
+  version = "0.0.0"
+  arch    = "amd64"
+}
+`
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ function.Function = &archNormalizeFunction{}
+
+// NewArchNormalizeFunction returns the arch_normalize function
+func NewArchNormalizeFunction() function.Function {
+	return &archNormalizeFunction{}
+}
+
+type archNormalizeFunction struct{}
+
+func (f *archNormalizeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "arch_normalize"
+}
+
+func (f *archNormalizeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the name that Nebraska uses for an arch.",
+		MarkdownDescription: "Returns the name that Nebraska uses for an arch, which may be given as it's known to Go, Docker or `uname -m`. For example `arm64` and `aarch64` both return `aarch64`, and `x86_64` returns `amd64`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "arch",
+				MarkdownDescription: "Arch to normalize.",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *archNormalizeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var arch string
+	resp.Error = req.Arguments.Get(ctx, &arch)
+	if resp.Error != nil {
+		return
+	}
+
+	normalized, err := nebraska.NormalizeArch(arch)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q is not an arch that Nebraska supports: one of %s", arch, strings.Join(nebraska.ValidArchs, ", ")))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, normalized)
+}
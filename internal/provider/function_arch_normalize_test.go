@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestArchNormalizeFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::arch_normalize("arm64")
+}
+`,
+				Check: resource.TestCheckOutput("test", "aarch64"),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::arch_normalize("riscv64")
+}
+`,
+				ExpectError: regexp.MustCompile(`is not an arch that Nebraska supports`),
+			},
+		},
+	})
+}
@@ -0,0 +1,53 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ function.Function = &packageTypeCodeFunction{}
+
+// NewPackageTypeCodeFunction returns the package_type_code function
+func NewPackageTypeCodeFunction() function.Function {
+	return &packageTypeCodeFunction{}
+}
+
+type packageTypeCodeFunction struct{}
+
+func (f *packageTypeCodeFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "package_type_code"
+}
+
+func (f *packageTypeCodeFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Returns the code that Nebraska uses for a package type.",
+		MarkdownDescription: fmt.Sprintf("Returns the number that Nebraska stores and returns for a package type, which is one of `%s`.", strings.Join(nebraska.ValidPackageTypes, "`, `")),
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "type",
+				MarkdownDescription: "Type of package.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *packageTypeCodeFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var pkgType string
+	resp.Error = req.Arguments.Get(ctx, &pkgType)
+	if resp.Error != nil {
+		return
+	}
+
+	pt, err := nebraska.PackageTypeFromString(pkgType)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, fmt.Sprintf("%q is not one of %s", pkgType, strings.Join(nebraska.ValidPackageTypes, ", ")))
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, int64(pt))
+}
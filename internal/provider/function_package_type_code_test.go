@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestPackageTypeCodeFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::package_type_code("flatcar")
+}
+`,
+				Check: resource.TestCheckOutput("test", "1"),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::package_type_code("deb")
+}
+`,
+				ExpectError: regexp.MustCompile(`"deb" is not one of flatcar, docker, rkt, other`),
+			},
+		},
+	})
+}
@@ -0,0 +1,51 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ function.Function = &postgresIntervalFunction{}
+
+// NewPostgresIntervalFunction returns the postgres_interval function
+func NewPostgresIntervalFunction() function.Function {
+	return &postgresIntervalFunction{}
+}
+
+type postgresIntervalFunction struct{}
+
+func (f *postgresIntervalFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "postgres_interval"
+}
+
+func (f *postgresIntervalFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Converts a duration into the interval format of group policies.",
+		MarkdownDescription: "Converts a duration such as `90m`, `1h30m` or `7d` into the interval format that Nebraska uses for the `policy_period_interval` and `policy_update_timeout` of groups, such as `90 minutes`. The largest unit that represents the duration exactly is used.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "duration",
+				MarkdownDescription: "A positive duration made up of days (`d`), hours (`h`), minutes (`m`) and seconds (`s`).",
+			},
+		},
+		Return: function.StringReturn{},
+	}
+}
+
+func (f *postgresIntervalFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var duration string
+	resp.Error = req.Arguments.Get(ctx, &duration)
+	if resp.Error != nil {
+		return
+	}
+
+	interval, err := nebraska.PostgresInterval(duration)
+	if err != nil {
+		resp.Error = function.NewArgumentFuncError(0, err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, interval)
+}
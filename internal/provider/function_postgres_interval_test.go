@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestPostgresIntervalFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::postgres_interval("90m")
+}
+`,
+				Check: resource.TestCheckOutput("test", "90 minutes"),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::postgres_interval("10 minutes")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid duration "10 minutes"`),
+			},
+		},
+	})
+}
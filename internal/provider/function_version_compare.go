@@ -0,0 +1,55 @@
+package provider
+
+import (
+	"context"
+
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ function.Function = &versionCompareFunction{}
+
+// NewVersionCompareFunction returns the version_compare function
+func NewVersionCompareFunction() function.Function {
+	return &versionCompareFunction{}
+}
+
+type versionCompareFunction struct{}
+
+func (f *versionCompareFunction) Metadata(ctx context.Context, req function.MetadataRequest, resp *function.MetadataResponse) {
+	resp.Name = "version_compare"
+}
+
+func (f *versionCompareFunction) Definition(ctx context.Context, req function.DefinitionRequest, resp *function.DefinitionResponse) {
+	resp.Definition = function.Definition{
+		Summary:             "Compares two package versions.",
+		MarkdownDescription: "Compares two package versions, such as Flatcar's `3510.2.1`, the way Nebraska does when it decides whether to offer an update. Returns `-1`, `0` or `1` when `a` is older than, the same as or newer than `b`.",
+		Parameters: []function.Parameter{
+			function.StringParameter{
+				Name:                "a",
+				MarkdownDescription: "Version to compare.",
+			},
+			function.StringParameter{
+				Name:                "b",
+				MarkdownDescription: "Version to compare with.",
+			},
+		},
+		Return: function.Int64Return{},
+	}
+}
+
+func (f *versionCompareFunction) Run(ctx context.Context, req function.RunRequest, resp *function.RunResponse) {
+	var a, b string
+	resp.Error = req.Arguments.Get(ctx, &a, &b)
+	if resp.Error != nil {
+		return
+	}
+
+	c, err := nebraska.CompareVersions(a, b)
+	if err != nil {
+		resp.Error = function.NewFuncError(err.Error())
+		return
+	}
+
+	resp.Error = resp.Result.Set(ctx, int64(c))
+}
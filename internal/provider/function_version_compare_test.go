@@ -0,0 +1,32 @@
+package provider
+
+import (
+	"regexp"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+)
+
+func TestVersionCompareFunction(t *testing.T) {
+	resource.UnitTest(t, resource.TestCase{
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::version_compare("3510.2.0", "3510.2.1")
+}
+`,
+				Check: resource.TestCheckOutput("test", "-1"),
+			},
+			{
+				Config: `
+output "test" {
+  value = provider::nebraska::version_compare("3510.2", "3510.2.1")
+}
+`,
+				ExpectError: regexp.MustCompile(`invalid version "3510.2"`),
+			},
+		},
+	})
+}
@@ -0,0 +1,114 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ list.ListResourceWithConfigure = &channelListResource{}
+
+// NewChannelListResource returns the nebraska_channel list resource
+func NewChannelListResource() list.ListResource {
+	return &channelListResource{}
+}
+
+type channelListResource struct {
+	channelResource
+}
+
+type channelListResourceModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	Arch          types.String `tfsdk:"arch"`
+	NameRegex     types.String `tfsdk:"name_regex"`
+}
+
+func (r *channelListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The release channels of an application, optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the application the channels belong to. Defaults to the provider's `application_id`.",
+			},
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return channels with this arch.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return channels with a name that matches this regular expression.",
+				Validators: []validator.String{
+					validRegexp(),
+				},
+			},
+		},
+	}
+}
+
+func (r *channelListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config channelListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, r.client)
+	if err != nil {
+		diags.AddError("Couldn't list channels", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	channelPage, err := r.client.WithContext(ctx).ListChannels(appID)
+	if err == nil && channelPage.Count != channelPage.TotalCount {
+		err = fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
+	}
+	if err != nil {
+		diags.AddError("Couldn't list channels", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	nameRegex := regexp.MustCompile(config.NameRegex.ValueString())
+	arch := config.Arch.ValueString()
+
+	var channels []nebraska.Channel
+	for _, c := range channelPage.Channels {
+		if arch != "" && c.Arch.String() != arch {
+			continue
+		}
+		if !nameRegex.MatchString(c.Name) {
+			continue
+		}
+		channels = append(channels, c)
+	}
+
+	stream.Results = listResults(ctx, req, channels, func(c nebraska.Channel, result *list.ListResult) {
+		result.DisplayName = fmt.Sprintf("%s (%s)", c.Name, c.Arch.String())
+		result.Diagnostics.Append(setIdentity(ctx, result.Identity, types.StringValue(appID), types.StringValue(c.ID))...)
+		if !req.IncludeResource {
+			return
+		}
+
+		m := channelResourceModel{
+			ID:            types.StringValue(c.ID),
+			ApplicationID: types.StringValue(appID),
+			ForceDetach:   types.BoolValue(false),
+			AdoptExisting: types.BoolValue(r.client.AdoptExisting),
+		}
+		m.setChannel(&c)
+		result.Diagnostics.Append(result.Resource.Set(ctx, m)...)
+	})
+}
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestChannelListResource(t *testing.T) {
+	responses := map[string]any{
+		"/api/apps/" + nebraska.FlatcarApplicationID + "/channels": nebraska.ChannelPage{
+			Count:      3,
+			TotalCount: 3,
+			Channels: []nebraska.Channel{
+				{ID: "c1", Name: "stable", Arch: nebraska.ArchAMD64, Color: "#000000"},
+				{ID: "c2", Name: "stable", Arch: nebraska.ArchAArch64, PackageID: "p1"},
+				{ID: "c3", Name: "beta", Arch: nebraska.ArchAMD64},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		config   map[string]tftypes.Value
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"stable (amd64)", "stable (aarch64)", "beta (amd64)"},
+		},
+		{
+			name: "arch",
+			config: map[string]tftypes.Value{
+				"arch": tftypes.NewValue(tftypes.String, "amd64"),
+			},
+			expected: []string{"stable (amd64)", "beta (amd64)"},
+		},
+		{
+			name: "name_regex",
+			config: map[string]tftypes.Value{
+				"name_regex": tftypes.NewValue(tftypes.String, "^sta"),
+			},
+			expected: []string{"stable (amd64)", "stable (aarch64)"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for _, r := range testListResource(t, "nebraska_channel", responses, tc.config, false) {
+				names = append(names, r.DisplayName)
+			}
+			assert.DeepEqual(t, names, tc.expected)
+		})
+	}
+
+	results := testListResource(t, "nebraska_channel", responses, map[string]tftypes.Value{
+		"arch": tftypes.NewValue(tftypes.String, "aarch64"),
+	}, true)
+	assert.Equal(t, len(results), 1)
+	assert.Assert(t, results[0].Identity["id"].Equal(tftypes.NewValue(tftypes.String, "c2")))
+	assert.Assert(t, results[0].Identity["application_id"].Equal(tftypes.NewValue(tftypes.String, nebraska.FlatcarApplicationID)))
+	assert.Assert(t, results[0].Resource["package_id"].Equal(tftypes.NewValue(tftypes.String, "p1")))
+	assert.Assert(t, results[0].Resource["force_detach"].Equal(tftypes.NewValue(tftypes.Bool, false)))
+}
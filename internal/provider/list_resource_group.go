@@ -0,0 +1,145 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ list.ListResourceWithConfigure = &groupListResource{}
+
+// NewGroupListResource returns the nebraska_group list resource
+func NewGroupListResource() list.ListResource {
+	return &groupListResource{}
+}
+
+type groupListResource struct {
+	groupResource
+}
+
+type groupListResourceModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	Arch          types.String `tfsdk:"arch"`
+	ChannelID     types.String `tfsdk:"channel_id"`
+	NameRegex     types.String `tfsdk:"name_regex"`
+}
+
+func (r *groupListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The groups of an application, optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the application the groups belong to. Defaults to the provider's `application_id`.",
+			},
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return groups that provide a channel with this arch.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
+			},
+			"channel_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return groups that provide this channel.",
+			},
+			"name_regex": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return groups with a name that matches this regular expression.",
+				Validators: []validator.String{
+					validRegexp(),
+				},
+			},
+		},
+	}
+}
+
+func (r *groupListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config groupListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, r.client)
+	if err != nil {
+		diags.AddError("Couldn't list groups", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	groupPage, err := r.client.WithContext(ctx).ListGroups(appID)
+	if err == nil && groupPage.Count != groupPage.TotalCount {
+		err = fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount)
+	}
+	if err != nil {
+		diags.AddError("Couldn't list groups", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	// Groups don't have an arch, so they're filtered by the arch of their
+	// channel
+	var archChannels map[string]bool
+	if arch := config.Arch.ValueString(); arch != "" {
+		channelPage, err := r.client.WithContext(ctx).ListChannels(appID)
+		if err == nil && channelPage.Count != channelPage.TotalCount {
+			err = fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
+		}
+		if err != nil {
+			diags.AddError("Couldn't list groups", err.Error())
+			stream.Results = list.ListResultsStreamDiagnostics(diags)
+			return
+		}
+		archChannels = map[string]bool{}
+		for _, c := range channelPage.Channels {
+			if c.Arch.String() == arch {
+				archChannels[c.ID] = true
+			}
+		}
+	}
+
+	nameRegex := regexp.MustCompile(config.NameRegex.ValueString())
+	channelID := config.ChannelID.ValueString()
+
+	var groups []nebraska.Group
+	for _, g := range groupPage.Groups {
+		if channelID != "" && g.ChannelID != channelID {
+			continue
+		}
+		if archChannels != nil && !archChannels[g.ChannelID] {
+			continue
+		}
+		if !nameRegex.MatchString(g.Name) {
+			continue
+		}
+		groups = append(groups, g)
+	}
+
+	stream.Results = listResults(ctx, req, groups, func(g nebraska.Group, result *list.ListResult) {
+		result.DisplayName = g.Name
+		result.Diagnostics.Append(setIdentity(ctx, result.Identity, types.StringValue(appID), types.StringValue(g.ID))...)
+		if !req.IncludeResource {
+			return
+		}
+
+		m := groupResourceModel{
+			ID:                             types.StringValue(g.ID),
+			ApplicationID:                  types.StringValue(appID),
+			DeletionProtection:             types.BoolValue(r.client.DeletionProtection),
+			AdoptExisting:                  types.BoolValue(r.client.AdoptExisting),
+			ActiveInstancesWindow:          types.StringValue("7d"),
+			AllowDeleteWithActiveInstances: types.BoolValue(false),
+		}
+		m.setGroup(&g)
+		result.Diagnostics.Append(result.Resource.Set(ctx, m)...)
+	})
+}
@@ -0,0 +1,82 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestGroupListResource(t *testing.T) {
+	responses := map[string]any{
+		"/api/apps/" + nebraska.FlatcarApplicationID + "/groups": nebraska.GroupPage{
+			Count:      3,
+			TotalCount: 3,
+			Groups: []nebraska.Group{
+				{ID: "g1", Name: "stable-amd64", ChannelID: "c1", Track: "stable"},
+				{ID: "g2", Name: "stable-arm64", ChannelID: "c2", PolicyPeriodInterval: "1 hours"},
+				{ID: "g3", Name: "beta", ChannelID: "c3"},
+			},
+		},
+		"/api/apps/" + nebraska.FlatcarApplicationID + "/channels": nebraska.ChannelPage{
+			Count:      3,
+			TotalCount: 3,
+			Channels: []nebraska.Channel{
+				{ID: "c1", Name: "stable", Arch: nebraska.ArchAMD64},
+				{ID: "c2", Name: "stable", Arch: nebraska.ArchAArch64},
+				{ID: "c3", Name: "beta", Arch: nebraska.ArchAMD64},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		config   map[string]tftypes.Value
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"stable-amd64", "stable-arm64", "beta"},
+		},
+		{
+			name: "arch",
+			config: map[string]tftypes.Value{
+				"arch": tftypes.NewValue(tftypes.String, "amd64"),
+			},
+			expected: []string{"stable-amd64", "beta"},
+		},
+		{
+			name: "channel_id",
+			config: map[string]tftypes.Value{
+				"channel_id": tftypes.NewValue(tftypes.String, "c3"),
+			},
+			expected: []string{"beta"},
+		},
+		{
+			name: "name_regex",
+			config: map[string]tftypes.Value{
+				"name_regex": tftypes.NewValue(tftypes.String, "^stable-"),
+			},
+			expected: []string{"stable-amd64", "stable-arm64"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for _, r := range testListResource(t, "nebraska_group", responses, tc.config, false) {
+				names = append(names, r.DisplayName)
+			}
+			assert.DeepEqual(t, names, tc.expected)
+		})
+	}
+
+	results := testListResource(t, "nebraska_group", responses, map[string]tftypes.Value{
+		"arch": tftypes.NewValue(tftypes.String, "aarch64"),
+	}, true)
+	assert.Equal(t, len(results), 1)
+	assert.Assert(t, results[0].Identity["id"].Equal(tftypes.NewValue(tftypes.String, "g2")))
+	assert.Assert(t, results[0].Resource["policy_period_interval"].Equal(tftypes.NewValue(tftypes.String, "1 hours")))
+	assert.Assert(t, results[0].Resource["description"].IsNull())
+	assert.Assert(t, results[0].Resource["active_instances_window"].Equal(tftypes.NewValue(tftypes.String, "7d")))
+}
@@ -0,0 +1,147 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/list/schema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var _ list.ListResourceWithConfigure = &packageListResource{}
+
+// NewPackageListResource returns the nebraska_package list resource
+func NewPackageListResource() list.ListResource {
+	return &packageListResource{}
+}
+
+type packageListResource struct {
+	packageResource
+}
+
+type packageListResourceModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	Arch          types.String `tfsdk:"arch"`
+	Type          types.String `tfsdk:"type"`
+	VersionPrefix types.String `tfsdk:"version_prefix"`
+	VersionRange  types.String `tfsdk:"version_range"`
+}
+
+func (r *packageListResource) ListResourceConfigSchema(ctx context.Context, req list.ListResourceSchemaRequest, resp *list.ListResourceSchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "The versioned packages of an application, optionally filtered.",
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "ID of the application the packages belong to. Defaults to the provider's `application_id`.",
+			},
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return packages with this arch.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
+			},
+			"type": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return packages of this type.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidPackageTypes...),
+				},
+			},
+			"version_prefix": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return packages with a version starting with this prefix.",
+			},
+			"version_range": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "Only return packages with a semantic version in this range, for example `>=3510.0.0 <3602.0.0`. Packages with versions that aren't semantic versions are skipped.",
+				Validators: []validator.String{
+					parsesAs{
+						description: "version range",
+						parse: func(s string) error {
+							_, err := semver.ParseRange(s)
+							return err
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+func (r *packageListResource) List(ctx context.Context, req list.ListRequest, stream *list.ListResultsStream) {
+	var config packageListResourceModel
+	diags := req.Config.Get(ctx, &config)
+	if diags.HasError() {
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	appID, err := applicationID(config.ApplicationID, r.client)
+	if err != nil {
+		diags.AddError("Couldn't list packages", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	versionPrefix := config.VersionPrefix.ValueString()
+	packagePage, err := r.client.WithContext(ctx).SearchPackages(appID, versionPrefix)
+	if err == nil && packagePage.Count != packagePage.TotalCount {
+		err = fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount)
+	}
+	if err != nil {
+		diags.AddError("Couldn't list packages", err.Error())
+		stream.Results = list.ListResultsStreamDiagnostics(diags)
+		return
+	}
+
+	inRange := func(semver.Version) bool { return true }
+	if v := config.VersionRange.ValueString(); v != "" {
+		inRange = semver.MustParseRange(v)
+	}
+	arch := config.Arch.ValueString()
+	pkgType := config.Type.ValueString()
+
+	var packages []nebraska.Package
+	for _, p := range packagePage.Packages {
+		if arch != "" && p.Arch.String() != arch {
+			continue
+		}
+		if pkgType != "" && p.Type.String() != pkgType {
+			continue
+		}
+		if !strings.HasPrefix(p.Version, versionPrefix) {
+			continue
+		}
+		if !config.VersionRange.IsNull() {
+			v, err := semver.Parse(p.Version)
+			if err != nil || !inRange(v) {
+				continue
+			}
+		}
+		packages = append(packages, p)
+	}
+
+	stream.Results = listResults(ctx, req, packages, func(p nebraska.Package, result *list.ListResult) {
+		result.DisplayName = fmt.Sprintf("%s (%s)", p.Version, p.Arch.String())
+		result.Diagnostics.Append(setIdentity(ctx, result.Identity, types.StringValue(appID), types.StringValue(p.ID))...)
+		if !req.IncludeResource {
+			return
+		}
+
+		m := packageResourceModel{
+			ID:            types.StringValue(p.ID),
+			ApplicationID: types.StringValue(appID),
+			OnDelete:      types.StringValue("fail"),
+		}
+		result.Diagnostics.Append(m.setPackage(ctx, &p)...)
+		result.Diagnostics.Append(result.Resource.Set(ctx, m)...)
+	})
+}
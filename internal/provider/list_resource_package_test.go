@@ -0,0 +1,81 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestPackageListResource(t *testing.T) {
+	responses := map[string]any{
+		"/api/apps/" + nebraska.FlatcarApplicationID + "/packages": nebraska.PackagePage{
+			Count:      4,
+			TotalCount: 4,
+			Packages: []nebraska.Package{
+				{ID: "p1", Version: "3510.2.1", Arch: nebraska.ArchAMD64, Type: 1, URL: "https://example.com/"},
+				{ID: "p2", Version: "3510.2.1", Arch: nebraska.ArchAArch64, Type: 1},
+				{ID: "p3", Version: "3602.2.0", Arch: nebraska.ArchAMD64, Type: 1},
+				{ID: "p4", Version: "latest", Arch: nebraska.ArchAMD64, Type: 4},
+			},
+		},
+	}
+
+	testCases := []struct {
+		name     string
+		config   map[string]tftypes.Value
+		expected []string
+	}{
+		{
+			name:     "all",
+			expected: []string{"3510.2.1 (amd64)", "3510.2.1 (aarch64)", "3602.2.0 (amd64)", "latest (amd64)"},
+		},
+		{
+			name: "arch",
+			config: map[string]tftypes.Value{
+				"arch": tftypes.NewValue(tftypes.String, "aarch64"),
+			},
+			expected: []string{"3510.2.1 (aarch64)"},
+		},
+		{
+			name: "type",
+			config: map[string]tftypes.Value{
+				"type": tftypes.NewValue(tftypes.String, "other"),
+			},
+			expected: []string{"latest (amd64)"},
+		},
+		{
+			name: "version_prefix",
+			config: map[string]tftypes.Value{
+				"version_prefix": tftypes.NewValue(tftypes.String, "3602."),
+			},
+			expected: []string{"3602.2.0 (amd64)"},
+		},
+		{
+			name: "version_range",
+			config: map[string]tftypes.Value{
+				"version_range": tftypes.NewValue(tftypes.String, ">=3510.0.0 <3600.0.0"),
+			},
+			expected: []string{"3510.2.1 (amd64)", "3510.2.1 (aarch64)"},
+		},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var names []string
+			for _, r := range testListResource(t, "nebraska_package", responses, tc.config, false) {
+				names = append(names, r.DisplayName)
+			}
+			assert.DeepEqual(t, names, tc.expected)
+		})
+	}
+
+	results := testListResource(t, "nebraska_package", responses, map[string]tftypes.Value{
+		"version_range": tftypes.NewValue(tftypes.String, ">=3600.0.0"),
+	}, true)
+	assert.Equal(t, len(results), 1)
+	assert.Assert(t, results[0].Identity["id"].Equal(tftypes.NewValue(tftypes.String, "p3")))
+	assert.Assert(t, results[0].Resource["version"].Equal(tftypes.NewValue(tftypes.String, "3602.2.0")))
+	assert.Assert(t, results[0].Resource["type"].Equal(tftypes.NewValue(tftypes.String, "flatcar")))
+	assert.Assert(t, results[0].Resource["on_delete"].Equal(tftypes.NewValue(tftypes.String, "fail")))
+}
@@ -3,110 +3,381 @@ package provider
 import (
 	"context"
 	"fmt"
+	"iter"
+	"os"
+	"regexp"
 	"strings"
+	"sync"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/float64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/action"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/function"
+	"github.com/hashicorp/terraform-plugin-framework/list"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/identityschema"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-mux/tf5muxserver"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func init() {
-	// Set descriptions to support markdown syntax, this will be used in
-	// document generation and the language server.
-	schema.DescriptionKind = schema.StringMarkdown
-
-	// Customize the content of descriptions when output.  Add defaults on
-	// to the exported descriptions if present.
-	schema.SchemaDescriptionBuilder = func(s *schema.Schema) string {
-		desc := s.Description
-		if s.Default != nil {
-			desc += fmt.Sprintf(" Defaults to `%v`.", s.Default)
+const (
+	defaultEndpoint = "http://localhost:8000"
+
+	providerApplicationIDDescription         = "The default application to create resources for. If omitted then `application_id` must be set on each individual resource. Can also be set with the environment variable `NEBRASKA_APPLICATION_ID`."
+	providerEndpointDescription              = "The address of the Nebraska server. Can also be set with the environment variable `NEBRASKA_ENDPOINT`."
+	providerUsernameDescription              = "The username for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_USERNAME`."
+	providerPasswordDescription              = "The password for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_PASSWORD`."
+	providerBearerTokenDescription           = "The bearer token for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_BEARER_TOKEN`."
+	providerDeletionProtectionDescription    = "The default value of `deletion_protection` for resources that support it. Defaults to `false`."
+	providerAdoptExistingDescription         = "The default value of `adopt_existing` for resources that support it. Defaults to `false`."
+	providerMaxRequestsPerSecondDescription  = "The maximum number of requests a second to send to the Nebraska server, in bursts of up to a second's worth. Requests over the limit wait for it. Defaults to `0`, no limit."
+	providerMaxConcurrentRequestsDescription = "The maximum number of requests to the Nebraska server that can be in flight at once. Requests over the limit wait for it. Defaults to `0`, no limit."
+	providerCacheReadsDescription            = "Read the channels, groups and packages of an application with one request for each, instead of one for every resource and data source, which speeds up refreshing large configurations. The requests are repeated after any change made by the provider. Defaults to `false`."
+)
+
+// httpLogEnv is the environment variable that enables logging the bodies of
+// the requests to Nebraska and their responses, at the level that it's set to
+const httpLogEnv = "TF_LOG_PROVIDER_NEBRASKA_HTTP"
+
+// httpURL matches URLs with an http or https scheme
+var httpURL = regexp.MustCompile(`^https?://[^/\s]+\S*$`)
+
+// New returns a new provider
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &nebraskaProvider{
+			version: version,
 		}
-		return strings.TrimSpace(desc)
 	}
 }
 
-// New returns a new provider
-func New(version string) func() *schema.Provider {
-	return func() *schema.Provider {
-		p := &schema.Provider{
-			Schema: map[string]*schema.Schema{
-				"application_id": {
-					Type:         schema.TypeString,
-					Optional:     true,
-					DefaultFunc:  schema.MultiEnvDefaultFunc([]string{"NEBRASKA_APPLICATION_ID"}, ""),
-					ValidateFunc: validation.StringIsNotWhiteSpace,
-					Description:  "The default application to create resources for. If omitted then `application_id` must be set on each individual resource. Can also be set with the environment variable `NEBRASKA_APPLICATION_ID`.",
-				},
-				"endpoint": {
-					Type:         schema.TypeString,
-					Optional:     true,
-					DefaultFunc:  schema.MultiEnvDefaultFunc([]string{"NEBRASKA_ENDPOINT"}, "http://localhost:8000"),
-					ValidateFunc: validation.IsURLWithHTTPorHTTPS,
-					Description:  "The address of the Nebraska server. Can also be set with the environment variable `NEBRASKA_ENDPOINT`.",
-				},
-				"username": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_USERNAME"}, ""),
-					Description: "The username for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_USERNAME`.",
-				},
-				"password": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_PASSWORD"}, ""),
-					Description: "The password for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_PASSWORD`.",
+// ProviderServer returns a server that serves the provider returned by New
+// together with the resources and data sources that are still implemented
+// with terraform-plugin-sdk
+func ProviderServer(ctx context.Context, version string) (func() tfprotov5.ProviderServer, error) {
+	servers := []func() tfprotov5.ProviderServer{
+		providerserver.NewProtocol5(New(version)()),
+		NewSDK(version)().GRPCProvider,
+	}
+
+	muxServer, err := tf5muxserver.NewMuxServer(ctx, servers...)
+	if err != nil {
+		return nil, err
+	}
+
+	return muxServer.ProviderServer, nil
+}
+
+var (
+	_ provider.ProviderWithActions       = &nebraskaProvider{}
+	_ provider.ProviderWithFunctions     = &nebraskaProvider{}
+	_ provider.ProviderWithListResources = &nebraskaProvider{}
+)
+
+type nebraskaProvider struct {
+	version string
+}
+
+type nebraskaProviderModel struct {
+	ApplicationID         types.String  `tfsdk:"application_id"`
+	Endpoint              types.String  `tfsdk:"endpoint"`
+	Username              types.String  `tfsdk:"username"`
+	Password              types.String  `tfsdk:"password"`
+	BearerToken           types.String  `tfsdk:"bearer_token"`
+	DeletionProtection    types.Bool    `tfsdk:"deletion_protection"`
+	AdoptExisting         types.Bool    `tfsdk:"adopt_existing"`
+	CacheReads            types.Bool    `tfsdk:"cache_reads"`
+	MaxRequestsPerSecond  types.Float64 `tfsdk:"max_requests_per_second"`
+	MaxConcurrentRequests types.Int64   `tfsdk:"max_concurrent_requests"`
+}
+
+func (p *nebraskaProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "nebraska"
+	resp.Version = p.version
+}
+
+func (p *nebraskaProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: providerApplicationIDDescription,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(regexp.MustCompile(`\S`), "must not be empty or consist entirely of whitespace characters"),
 				},
-				"bearer_token": {
-					Type:        schema.TypeString,
-					Optional:    true,
-					Sensitive:   true,
-					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_BEARER_TOKEN"}, ""),
-					Description: "The bearer token for authentication to the Nebraska server. Can also be set with the environment variable `NEBRASKA_BEARER_TOKEN`.",
+			},
+			"endpoint": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: providerEndpointDescription,
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURL, "must be a URL with an http or https scheme"),
 				},
 			},
-			DataSourcesMap: map[string]*schema.Resource{
-				"nebraska_channel": dataSourceChannel(),
-				"nebraska_group":   dataSourceGroup(),
-				"nebraska_package": dataSourcePackage(),
+			"username": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: providerUsernameDescription,
 			},
-			ResourcesMap: map[string]*schema.Resource{
-				"nebraska_channel": resourceChannel(),
-				"nebraska_group":   resourceGroup(),
-				"nebraska_package": resourcePackage(),
+			"password": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: providerPasswordDescription,
 			},
-		}
+			"bearer_token": schema.StringAttribute{
+				Optional:            true,
+				Sensitive:           true,
+				MarkdownDescription: providerBearerTokenDescription,
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: providerDeletionProtectionDescription,
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: providerAdoptExistingDescription,
+			},
+			"cache_reads": schema.BoolAttribute{
+				Optional:            true,
+				MarkdownDescription: providerCacheReadsDescription,
+			},
+			"max_requests_per_second": schema.Float64Attribute{
+				Optional:            true,
+				MarkdownDescription: providerMaxRequestsPerSecondDescription,
+				Validators: []validator.Float64{
+					float64validator.AtLeast(0),
+				},
+			},
+			"max_concurrent_requests": schema.Int64Attribute{
+				Optional:            true,
+				MarkdownDescription: providerMaxConcurrentRequestsDescription,
+				Validators: []validator.Int64{
+					int64validator.AtLeast(0),
+				},
+			},
+		},
+	}
+}
+
+func (p *nebraskaProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config nebraskaProviderModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client, err := newAPIClient(ctx, providerSettings{
+		Endpoint:              stringOrEnv(config.Endpoint, "NEBRASKA_ENDPOINT", defaultEndpoint),
+		UserAgent:             fmt.Sprintf("Terraform/%s (+https://www.terraform.io) terraform-provider-nebraska/%s", req.TerraformVersion, p.version),
+		Username:              stringOrEnv(config.Username, "NEBRASKA_USERNAME", ""),
+		Password:              stringOrEnv(config.Password, "NEBRASKA_PASSWORD", ""),
+		BearerToken:           stringOrEnv(config.BearerToken, "NEBRASKA_BEARER_TOKEN", ""),
+		ApplicationID:         stringOrEnv(config.ApplicationID, "NEBRASKA_APPLICATION_ID", ""),
+		DeletionProtection:    config.DeletionProtection.ValueBool(),
+		AdoptExisting:         config.AdoptExisting.ValueBool(),
+		CacheReads:            config.CacheReads.ValueBool(),
+		MaxRequestsPerSecond:  config.MaxRequestsPerSecond.ValueFloat64(),
+		MaxConcurrentRequests: int(config.MaxConcurrentRequests.ValueInt64()),
+		EndpointUnknown:       config.Endpoint.IsUnknown(),
+	})
+	if err != nil {
+		resp.Diagnostics.AddWarning("Couldn't discover the capabilities of the Nebraska server", err.Error())
+	}
+
+	resp.DataSourceData = client
+	resp.ResourceData = client
+	resp.ListResourceData = client
+	resp.ActionData = client
+}
+
+func (p *nebraskaProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		NewChannelResource,
+		NewChannelPackageBindingResource,
+		NewGroupResource,
+		NewPackageResource,
+	}
+}
+
+func (p *nebraskaProvider) Actions(ctx context.Context) []func() action.Action {
+	return []func() action.Action{
+		NewPauseGroupAction,
+		NewPromotePackageAction,
+		NewResumeGroupAction,
+	}
+}
+
+func (p *nebraskaProvider) ListResources(ctx context.Context) []func() list.ListResource {
+	return []func() list.ListResource{
+		NewChannelListResource,
+		NewGroupListResource,
+		NewPackageListResource,
+	}
+}
+
+func (p *nebraskaProvider) Functions(ctx context.Context) []func() function.Function {
+	return []func() function.Function{
+		NewArchNormalizeFunction,
+		NewPackageTypeCodeFunction,
+		NewPostgresIntervalFunction,
+		NewVersionCompareFunction,
+	}
+}
+
+func (p *nebraskaProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		NewChannelDataSource,
+		NewGroupDataSource,
+		NewPackageDataSource,
+	}
+}
+
+// providerSettings are the settings of the provider, from its configuration
+// or the environment
+type providerSettings struct {
+	Endpoint              string
+	UserAgent             string
+	Username              string
+	Password              string
+	BearerToken           string
+	ApplicationID         string
+	DeletionProtection    bool
+	AdoptExisting         bool
+	CacheReads            bool
+	MaxRequestsPerSecond  float64
+	MaxConcurrentRequests int
+	// EndpointUnknown is true when the endpoint won't be known until apply
+	EndpointUnknown bool
+}
+
+// newAPIClient returns the client for the settings, which both the framework
+// and SDK parts of the provider are configured with. Unless the endpoint is
+// unknown, it discovers the capabilities of the server, and returns the
+// error if it can't, in which case only the features that need them fail.
+func newAPIClient(ctx context.Context, s providerSettings) (*apiClient, error) {
+	c := nebraska.New(s.Endpoint, s.UserAgent, s.Username, s.Password, s.BearerToken)
+	client := &apiClient{
+		Client:             c,
+		ApplicationID:      s.ApplicationID,
+		DeletionProtection: s.DeletionProtection,
+		AdoptExisting:      s.AdoptExisting,
+	}
+	if s.CacheReads {
+		client.cache = sharedReadCache(c.BaseURL, s.Username, s.Password, s.BearerToken)
+	}
+	c.SetLimiter(sharedLimiter(c.BaseURL, s.MaxRequestsPerSecond, s.MaxConcurrentRequests))
+	c.SetLogBodies(os.Getenv(httpLogEnv) != "")
+	if s.EndpointUnknown {
+		return client, nil
+	}
+
+	client.Capabilities, client.capabilitiesErr = client.WithContext(ctx).Client.Capabilities()
+
+	return client, client.capabilitiesErr
+}
 
-		p.ConfigureContextFunc = providerConfigure(version, p)
+// limiters are the limiters of the Nebraska servers by endpoint and limits.
+// The framework and SDK parts of the provider share them, so that their
+// requests count towards the same limits.
+var (
+	limitersMu sync.Mutex
+	limiters   = map[string]*nebraska.Limiter{}
+)
+
+// sharedLimiter returns the limiter of the Nebraska server at the endpoint
+func sharedLimiter(endpoint string, requestsPerSecond float64, maxConcurrentRequests int) *nebraska.Limiter {
+	limitersMu.Lock()
+	defer limitersMu.Unlock()
 
-		return p
+	key := fmt.Sprintf("%s %g %d", endpoint, requestsPerSecond, maxConcurrentRequests)
+	l, ok := limiters[key]
+	if !ok {
+		l = nebraska.NewLimiter(requestsPerSecond, maxConcurrentRequests)
+		limiters[key] = l
 	}
+
+	return l
 }
 
 type apiClient struct {
 	*nebraska.Client
-	ApplicationID string
+	ApplicationID      string
+	DeletionProtection bool
+	AdoptExisting      bool
+	// Capabilities are those of the server, or nil if they weren't
+	// discovered when the provider was configured
+	Capabilities *nebraska.Capabilities
+
+	// capabilitiesErr is why the capabilities couldn't be discovered
+	capabilitiesErr error
+	cache           *readCache
 }
 
-func providerConfigure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-		username := d.Get("username").(string)
-		password := d.Get("password").(string)
-		bearerToken := d.Get("bearer_token").(string)
+// WithContext returns a copy of the client that sends requests with the
+// context of the Terraform operation, so that they're cancelled with it,
+// traced as part of the caller's trace and logged with its loggers
+func (c *apiClient) WithContext(ctx context.Context) *apiClient {
+	ctx = traceParentContext(ctx)
+	if os.Getenv(httpLogEnv) != "" {
+		ctx = tflog.NewSubsystem(ctx, nebraska.LogSubsystem, tflog.WithLevelFromEnv("TF_LOG_PROVIDER_NEBRASKA", nebraska.LogSubsystem))
+	}
+	cp := *c
+	cp.Client = c.Client.WithContext(ctx)
 
-		c := nebraska.New(d.Get("endpoint").(string), p.UserAgent("terraform-provider-nebraska", version), username, password, bearerToken)
-		return &apiClient{
-			Client:        c,
-			ApplicationID: d.Get("application_id").(string),
-		}, nil
+	return &cp
+}
+
+// requireFeature adds an error for the attribute to diags if the server
+// doesn't support the feature that it needs, or if the capabilities of the
+// server couldn't be discovered
+func (c *apiClient) requireFeature(feature nebraska.Feature, attr path.Path, diags *diag.Diagnostics) {
+	if c.capabilitiesErr != nil {
+		diags.AddAttributeError(
+			attr,
+			fmt.Sprintf("%s requires Nebraska >= %s", attr, feature.MinimumVersion()),
+			fmt.Sprintf("Couldn't check that the Nebraska server at %s supports %s: %s", c.BaseURL, feature, c.capabilitiesErr),
+		)
+		return
 	}
+	if c.Capabilities == nil || c.Capabilities.Supports(feature) {
+		return
+	}
+
+	diags.AddAttributeError(
+		attr,
+		fmt.Sprintf("%s requires Nebraska >= %s", attr, feature.MinimumVersion()),
+		fmt.Sprintf("The Nebraska server at %s is running %s, which doesn't support %s. Upgrade the server or remove %s from the configuration.", c.BaseURL, c.Capabilities.Version, feature, attr),
+	)
 }
 
-func getApplicationID(d *schema.ResourceData, client *apiClient) (string, error) {
-	if id, ok := d.GetOk("application_id"); ok {
-		return id.(string), nil
+// stringOrEnv returns the configured value, or the value of the environment
+// variable when it isn't configured
+func stringOrEnv(v types.String, key, def string) string {
+	if !v.IsNull() {
+		return v.ValueString()
+	}
+	if env := os.Getenv(key); env != "" {
+		return env
+	}
+
+	return def
+}
+
+// applicationID returns the configured application, or the provider's default
+// application when it isn't configured
+func applicationID(v types.String, client *apiClient) (string, error) {
+	if v.ValueString() != "" {
+		return v.ValueString(), nil
 	}
 	if client.ApplicationID != "" {
 		return client.ApplicationID, nil
@@ -114,3 +385,164 @@ func getApplicationID(d *schema.ResourceData, client *apiClient) (string, error)
 
 	return "", fmt.Errorf("application_id: required field is not set")
 }
+
+// configuredClient returns the client that the provider passes to resources
+// and data sources. It returns nil until the provider has been configured.
+func configuredClient(providerData any, diags *diag.Diagnostics) *apiClient {
+	if providerData == nil {
+		return nil
+	}
+	c, ok := providerData.(*apiClient)
+	if !ok {
+		diags.AddError("Unexpected provider data", fmt.Sprintf("Expected *apiClient, got: %T.", providerData))
+		return nil
+	}
+
+	return c
+}
+
+// optionalString returns the value read from Nebraska for an optional
+// attribute. Nebraska doesn't distinguish between an empty value and no value,
+// so an empty value is null unless the attribute was set to "".
+func optionalString(prior types.String, v string) types.String {
+	if v == "" && prior.IsNull() {
+		return types.StringNull()
+	}
+
+	return types.StringValue(v)
+}
+
+// nullIfEmpty returns null for an empty string, which is how
+// terraform-plugin-sdk stored optional attributes that weren't set
+func nullIfEmpty(v types.String) types.String {
+	if v.ValueString() == "" {
+		return types.StringNull()
+	}
+
+	return v
+}
+
+// setProviderDefault sets a boolean attribute in the plan to the provider's
+// default when it isn't configured
+func setProviderDefault(ctx context.Context, config tfsdk.Config, plan *tfsdk.Plan, p path.Path, value bool) diag.Diagnostics {
+	var v types.Bool
+	diags := config.GetAttribute(ctx, p, &v)
+	if diags.HasError() || !v.IsNull() {
+		return diags
+	}
+
+	return plan.SetAttribute(ctx, p, value)
+}
+
+// importApplicationID supports importing resources by `<application_id>/<id>`
+// as well as by id, for resources that don't belong to the provider's default
+// application, and by identity
+func importApplicationID(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	if req.ID == "" && req.Identity != nil {
+		var identity resourceIdentityModel
+		resp.Diagnostics.Append(req.Identity.Get(ctx, &identity)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("application_id"), nullIfEmpty(identity.ApplicationID))...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), identity.ID)...)
+		return
+	}
+
+	id := req.ID
+	if appID, resourceID, ok := strings.Cut(req.ID, "/"); ok {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("application_id"), appID)...)
+		id = resourceID
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("id"), id)...)
+}
+
+// resourceIdentityModel is the identity of the objects that belong to an
+// application
+type resourceIdentityModel struct {
+	ApplicationID types.String `tfsdk:"application_id"`
+	ID            types.String `tfsdk:"id"`
+}
+
+// resourceIdentitySchema returns the identity schema of the objects that
+// belong to an application
+func resourceIdentitySchema(kind string) identityschema.Schema {
+	return identityschema.Schema{
+		Attributes: map[string]identityschema.Attribute{
+			"application_id": identityschema.StringAttribute{
+				OptionalForImport: true,
+				Description:       fmt.Sprintf("ID of the application the %s belongs to. Defaults to the provider's `application_id`.", kind),
+			},
+			"id": identityschema.StringAttribute{
+				RequiredForImport: true,
+				Description:       fmt.Sprintf("ID of the %s.", kind),
+			},
+		},
+	}
+}
+
+// setIdentity sets the identity of a resource, when Terraform supports
+// resource identity
+func setIdentity(ctx context.Context, identity *tfsdk.ResourceIdentity, appID, id types.String) diag.Diagnostics {
+	if identity == nil || id.IsUnknown() || id.IsNull() {
+		return nil
+	}
+
+	return identity.Set(ctx, resourceIdentityModel{
+		ApplicationID: appID,
+		ID:            id,
+	})
+}
+
+// listResults returns the results of a list request for objects, stopping at
+// the limit of the request. set populates the result for an object.
+func listResults[T any](ctx context.Context, req list.ListRequest, objects []T, set func(T, *list.ListResult)) iter.Seq[list.ListResult] {
+	return func(yield func(list.ListResult) bool) {
+		for i, o := range objects {
+			if req.Limit > 0 && int64(i) >= req.Limit {
+				return
+			}
+			result := req.NewListResult(ctx)
+			set(o, &result)
+			if !yield(result) {
+				return
+			}
+		}
+	}
+}
+
+// parsesAs validates that a string can be parsed by parse
+type parsesAs struct {
+	description string
+	parse       func(string) error
+}
+
+var _ validator.String = parsesAs{}
+
+func (v parsesAs) Description(ctx context.Context) string {
+	return fmt.Sprintf("value must be a valid %s", v.description)
+}
+
+func (v parsesAs) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v parsesAs) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if err := v.parse(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid Attribute Value", fmt.Sprintf("Attribute %s %s: %s", req.Path, v.Description(ctx), err))
+	}
+}
+
+// validRegexp validates that a string is a regular expression
+func validRegexp() validator.String {
+	return parsesAs{
+		description: "regular expression",
+		parse: func(s string) error {
+			_, err := regexp.Compile(s)
+			return err
+		},
+	}
+}
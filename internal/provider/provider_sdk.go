@@ -0,0 +1,144 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+)
+
+func init() {
+	// Set descriptions to support markdown syntax, this will be used in
+	// document generation and the language server.
+	schema.DescriptionKind = schema.StringMarkdown
+
+	// Customize the content of descriptions when output.  Add defaults on
+	// to the exported descriptions if present.
+	schema.SchemaDescriptionBuilder = func(s *schema.Schema) string {
+		desc := s.Description
+		if s.Default != nil {
+			desc += fmt.Sprintf(" Defaults to `%v`.", s.Default)
+		}
+		return strings.TrimSpace(desc)
+	}
+}
+
+// NewSDK returns the part of the provider that is still implemented with
+// terraform-plugin-sdk. It is served alongside the provider returned by New
+// until everything has been migrated to terraform-plugin-framework.
+//
+// The provider schema must stay identical to the one in New. Validation of
+// the provider configuration happens in New, so that errors aren't reported
+// twice.
+func NewSDK(version string) func() *schema.Provider {
+	return func() *schema.Provider {
+		p := &schema.Provider{
+			Schema: map[string]*schema.Schema{
+				"application_id": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_APPLICATION_ID"}, ""),
+					Description: providerApplicationIDDescription,
+				},
+				"endpoint": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_ENDPOINT"}, defaultEndpoint),
+					Description: providerEndpointDescription,
+				},
+				"username": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_USERNAME"}, ""),
+					Description: providerUsernameDescription,
+				},
+				"password": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_PASSWORD"}, ""),
+					Description: providerPasswordDescription,
+				},
+				"bearer_token": {
+					Type:        schema.TypeString,
+					Optional:    true,
+					Sensitive:   true,
+					DefaultFunc: schema.MultiEnvDefaultFunc([]string{"NEBRASKA_BEARER_TOKEN"}, ""),
+					Description: providerBearerTokenDescription,
+				},
+				"deletion_protection": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: providerDeletionProtectionDescription,
+				},
+				"adopt_existing": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: providerAdoptExistingDescription,
+				},
+				"cache_reads": {
+					Type:        schema.TypeBool,
+					Optional:    true,
+					Description: providerCacheReadsDescription,
+				},
+				"max_requests_per_second": {
+					Type:        schema.TypeFloat,
+					Optional:    true,
+					Description: providerMaxRequestsPerSecondDescription,
+				},
+				"max_concurrent_requests": {
+					Type:        schema.TypeInt,
+					Optional:    true,
+					Description: providerMaxConcurrentRequestsDescription,
+				},
+			},
+			DataSourcesMap: map[string]*schema.Resource{
+				"nebraska_channels":     dataSourceChannels(),
+				"nebraska_groups":       dataSourceGroups(),
+				"nebraska_packages":     dataSourcePackages(),
+				"nebraska_update_check": dataSourceUpdateCheck(),
+			},
+		}
+
+		p.ConfigureContextFunc = providerConfigure(version, p)
+
+		return p
+	}
+}
+
+func providerConfigure(version string, p *schema.Provider) func(context.Context, *schema.ResourceData) (interface{}, diag.Diagnostics) {
+	return func(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
+		raw := d.GetRawConfig()
+		// The framework part of the provider warns when the capabilities
+		// of the server can't be discovered, so that it isn't reported twice
+		client, _ := newAPIClient(ctx, providerSettings{
+			Endpoint:              d.Get("endpoint").(string),
+			UserAgent:             p.UserAgent("terraform-provider-nebraska", version),
+			Username:              d.Get("username").(string),
+			Password:              d.Get("password").(string),
+			BearerToken:           d.Get("bearer_token").(string),
+			ApplicationID:         d.Get("application_id").(string),
+			DeletionProtection:    d.Get("deletion_protection").(bool),
+			AdoptExisting:         d.Get("adopt_existing").(bool),
+			CacheReads:            d.Get("cache_reads").(bool),
+			MaxRequestsPerSecond:  d.Get("max_requests_per_second").(float64),
+			MaxConcurrentRequests: d.Get("max_concurrent_requests").(int),
+			EndpointUnknown:       !raw.IsNull() && !raw.GetAttr("endpoint").IsKnown(),
+		})
+
+		return client, nil
+	}
+}
+
+func getApplicationID(d *schema.ResourceData, client *apiClient) (string, error) {
+	if id, ok := d.GetOk("application_id"); ok {
+		return id.(string), nil
+	}
+	if client.ApplicationID != "" {
+		return client.ApplicationID, nil
+	}
+
+	return "", fmt.Errorf("application_id: required field is not set")
+}
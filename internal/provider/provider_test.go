@@ -1,32 +1,718 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/acctest"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest/recorder"
 )
 
-// providerFactories are used to instantiate a provider during acceptance testing.
-// The factory function will be invoked for every Terraform CLI command executed
-// to create a provider server to which the CLI can reattach.
-var providerFactories = map[string]func() (*schema.Provider, error){
-	"nebraska": func() (*schema.Provider, error) {
-		return New("dev")(), nil
+// testAccPrefix prefixes the names of the objects created by the acceptance
+// tests, so that the sweepers can find the ones left behind by failed runs.
+// Packages have it in the pre-release part of their version.
+const testAccPrefix = "tf-acc-test"
+
+// protoV5ProviderFactories are used to instantiate a provider during acceptance
+// testing. The factory function will be invoked for every Terraform CLI command
+// executed to create a provider server to which the CLI can reattach.
+var protoV5ProviderFactories = map[string]func() (tfprotov5.ProviderServer, error){
+	"nebraska": func() (tfprotov5.ProviderServer, error) {
+		providerServer, err := ProviderServer(context.Background(), "dev")
+		if err != nil {
+			return nil, err
+		}
+
+		return providerServer(), nil
+	},
+}
+
+// sdkProvider is the last release of the provider that was implemented with
+// terraform-plugin-sdk, used to test that its state is upgraded. Releases
+// before 1.0.0 are SDK-based.
+var sdkProvider = map[string]resource.ExternalProvider{
+	"nebraska": {
+		Source:            "utilitywarehouse/nebraska",
+		VersionConstraint: "< 1.0.0",
 	},
 }
 
+// TestMain runs the sweepers when the tests are run with -sweep
+func TestMain(m *testing.M) {
+	resource.TestMain(m)
+}
+
 func TestProvider(t *testing.T) {
-	if err := New("dev")().InternalValidate(); err != nil {
+	if err := NewSDK("dev")().InternalValidate(); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// The mux server fails to serve the schema if the provider schemas of
+	// the framework and SDK providers differ
+	providerServer, err := ProviderServer(context.Background(), "dev")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	resp, err := providerServer().GetProviderSchema(context.Background(), &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Errorf("%s: %s", d.Summary, d.Detail)
+	}
+}
+
+func TestProviderSDKCapabilities(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	defer fake.Close()
+	fake.SetVersion("2.7.0")
+
+	p := NewSDK("dev")()
+	diags := p.Configure(context.Background(), terraform.NewResourceConfigRaw(map[string]any{
+		"endpoint": fake.URL,
+	}))
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	// The data sources of the SDK part gate features like the framework part
+	client := p.Meta().(*apiClient)
+	if client.Capabilities == nil || client.Capabilities.Version.String() != "2.7.0" || client.Capabilities.Supports(nebraska.FeaturePackageSearch) {
+		t.Fatalf("got capabilities %+v", client.Capabilities)
+	}
+}
+
+// testUpgradeResourceState upgrades state written by terraform-plugin-sdk at
+// schema version 0 and returns the upgraded attributes
+func testUpgradeResourceState(t *testing.T, typeName, state string) map[string]tftypes.Value {
+	t.Helper()
+
+	ctx := context.Background()
+	server := providerserver.NewProtocol5(New("dev")())()
+
+	schemaResp, err := server.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range schemaResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	resp, err := server.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: []byte(state)},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range resp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	v, err := resp.UpgradedState.Unmarshal(schemaResp.ResourceSchemas[typeName].ValueType())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := v.As(&attrs); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return attrs
+}
+
+// testPlanSDKState upgrades the state in testdata/sdk-state/<name>.json,
+// after replacing the ids in it, refreshes and plans it with the
+// configuration like Terraform does, and returns the attributes that the plan
+// changes.
+//
+// The states were written by the terraform-plugin-sdk implementation of the
+// provider, from before its migration to terraform-plugin-framework, applying
+// the resources over the plugin protocol against a nebraskatest server.
+func testPlanSDKState(t *testing.T, server *testServer, typeName, name string, ids map[string]string, config map[string]tftypes.Value) []string {
+	t.Helper()
+
+	return testPlanResource(t, server, typeName, testSDKState(t, server, typeName, name, ids), config)
+}
+
+// testSDKState returns the state in testdata/sdk-state/<name>.json, after
+// replacing the ids in it, upgraded and refreshed like testPlanSDKState
+func testSDKState(t *testing.T, server *testServer, typeName, name string, ids map[string]string) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	ctx := context.Background()
+	state, err := os.ReadFile(filepath.Join("testdata", "sdk-state", name+".json"))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	var oldnew []string
+	for old, id := range ids {
+		oldnew = append(oldnew, old, id)
+	}
+	state = []byte(strings.NewReplacer(oldnew...).Replace(string(state)))
+
+	upgradeResp, err := server.UpgradeResourceState(ctx, &tfprotov5.UpgradeResourceStateRequest{
+		TypeName: typeName,
+		Version:  0,
+		RawState: &tfprotov5.RawState{JSON: state},
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range upgradeResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+	readResp, err := server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: upgradeResp.UpgradedState,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range readResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	return readResp.NewState
+}
+
+// testImportResource imports the resource with the id and refreshes it like
+// Terraform does, and returns its state
+func testImportResource(t *testing.T, server *testServer, typeName, id string) *tfprotov5.DynamicValue {
+	t.Helper()
+
+	ctx := context.Background()
+	importResp, err := server.ImportResourceState(ctx, &tfprotov5.ImportResourceStateRequest{
+		TypeName: typeName,
+		ID:       id,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range importResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+	if len(importResp.ImportedResources) != 1 {
+		t.Fatalf("got %d imported resources", len(importResp.ImportedResources))
+	}
+	readResp, err := server.ReadResource(ctx, &tfprotov5.ReadResourceRequest{
+		TypeName:     typeName,
+		CurrentState: importResp.ImportedResources[0].State,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range readResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	return readResp.NewState
+}
+
+// testPlanResource plans the prior state with the configuration like
+// Terraform does, and returns the attributes that the plan changes
+func testPlanResource(t *testing.T, server *testServer, typeName string, priorState *tfprotov5.DynamicValue, config map[string]tftypes.Value) []string {
+	t.Helper()
+
+	ctx := context.Background()
+	schema := server.schema.ResourceSchemas[typeName]
+	prior, err := priorState.Unmarshal(schema.ValueType())
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	configValue := testConfigValue(schema.Block, config)
+	proposed, err := tfprotov5.NewDynamicValue(schema.ValueType(), testProposedNewState(schema.Block, prior, configValue))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	configDynamic, err := tfprotov5.NewDynamicValue(schema.ValueType(), configValue)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       priorState,
+		ProposedNewState: &proposed,
+		Config:           &configDynamic,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range planResp.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	var changed []string
+	priorAttrs := testAttributes(t, schema.ValueType(), priorState)
+	for name, v := range testAttributes(t, schema.ValueType(), planResp.PlannedState) {
+		if !v.Equal(priorAttrs[name]) {
+			changed = append(changed, name)
+		}
+	}
+	for _, p := range planResp.RequiresReplace {
+		changed = append(changed, "replace "+p.String())
+	}
+	slices.Sort(changed)
+
+	return changed
+}
+
+// testSDKStateObjects creates the objects of the states in
+// testdata/sdk-state on the fake, and returns their new ids by the ids in
+// the states
+func testSDKStateObjects(t *testing.T, fake *nebraskatest.Server) map[string]string {
+	t.Helper()
+
+	c := fake.Client()
+	appID := nebraska.FlatcarApplicationID
+	pkg, err := c.AddPackage(appID, &nebraska.AddPackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{},
+		FlatcarAction:     &nebraska.FlatcarActionInput{},
+		Filename:          "flatcar_production_update.gz",
+		Type:              nebraska.PackageTypeFlatcar,
+		URL:               "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/",
+		Version:           "2905.2.0",
+	})
+	if err != nil {
 		t.Fatalf("err: %s", err)
 	}
+	pkgWithAction, err := c.AddPackage(appID, &nebraska.AddPackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{},
+		Description:       "Flatcar 2905.2.1",
+		FlatcarAction:     &nebraska.FlatcarActionInput{Sha256: "cSBzKN9c20GxWnZs7zFUqSgJjmU7Iqh1Eu2/mS5J5Jc="},
+		Filename:          "flatcar_production_update.gz",
+		Hash:              "r3nufcxgMTZaxYEqL+x2zIoeClk=",
+		Size:              "465881871",
+		Type:              nebraska.PackageTypeFlatcar,
+		URL:               "https://update.release.flatcar-linux.net/amd64-usr/2905.2.1/",
+		Version:           "2905.2.1",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ch, err := c.AddChannel(appID, &nebraska.AddChannelInput{
+		Name:      "stable",
+		Arch:      nebraska.ArchAMD64,
+		Color:     "#1fbb86",
+		PackageID: pkg.ID,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	group, err := c.AddGroup(appID, &nebraska.AddGroupInput{
+		Name:                      "Stable",
+		Description:               "Stable machines",
+		ChannelID:                 ch.ID,
+		PolicyUpdatesEnabled:      true,
+		PolicyTimezone:            "Europe/London",
+		PolicyPeriodInterval:      "15 minutes",
+		PolicyMaxUpdatesPerPeriod: 9999999,
+		PolicyUpdateTimeout:       "60 minutes",
+		Track:                     "stable",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return map[string]string{
+		"9376a543-d590-4838-b768-84eda3ce93fd": pkg.ID,
+		"2e29f185-e9aa-404b-8dfc-dab7cc0c2c98": pkgWithAction.ID,
+		"30a3a6f5-0315-4204-a760-ffc0f2916485": ch.ID,
+		"5290f4fa-8585-4831-8748-29bb310eb864": group.ID,
+	}
+}
+
+// testConfigValue returns a configuration of the block with the given
+// attributes, null attributes otherwise and no nested blocks unless they're
+// given, like Terraform sends
+func testConfigValue(block *tfprotov5.SchemaBlock, attrs map[string]tftypes.Value) tftypes.Value {
+	typ := block.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range typ.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+	}
+	for _, b := range block.BlockTypes {
+		values[b.TypeName] = tftypes.NewValue(typ.AttributeTypes[b.TypeName], []tftypes.Value{})
+	}
+	for name, v := range attrs {
+		values[name] = v
+	}
+
+	return tftypes.NewValue(typ, values)
+}
+
+// testProposedNewState returns the state that Terraform proposes from the
+// prior state and the configuration, which keeps the prior values of the
+// computed attributes that aren't configured
+func testProposedNewState(block *tfprotov5.SchemaBlock, prior, config tftypes.Value) tftypes.Value {
+	if prior.IsNull() || config.IsNull() {
+		return config
+	}
+	var priorValues, configValues map[string]tftypes.Value
+	if err := prior.As(&priorValues); err != nil {
+		panic(err)
+	}
+	if err := config.As(&configValues); err != nil {
+		panic(err)
+	}
+	values := map[string]tftypes.Value{}
+	for _, a := range block.Attributes {
+		values[a.Name] = configValues[a.Name]
+		if a.Computed && configValues[a.Name].IsNull() {
+			values[a.Name] = priorValues[a.Name]
+		}
+	}
+	for _, b := range block.BlockTypes {
+		var priorElems, configElems []tftypes.Value
+		if err := priorValues[b.TypeName].As(&priorElems); err != nil {
+			panic(err)
+		}
+		if err := configValues[b.TypeName].As(&configElems); err != nil {
+			panic(err)
+		}
+		elems := []tftypes.Value{}
+		for i, e := range configElems {
+			if i < len(priorElems) {
+				e = testProposedNewState(b.Block, priorElems[i], e)
+			}
+			elems = append(elems, e)
+		}
+		values[b.TypeName] = tftypes.NewValue(config.Type().(tftypes.Object).AttributeTypes[b.TypeName], elems)
+	}
+
+	return tftypes.NewValue(config.Type(), values)
+}
+
+// testServer is a provider server configured against a Nebraska server that
+// serves canned responses
+type testServer struct {
+	tfprotov5.ProviderServer
+	schema *tfprotov5.GetProviderSchemaResponse
+	// writes are the paths and bodies of the requests other than GETs that
+	// the Nebraska server received
+	writes []testWrite
+}
+
+// testWrite is a request that writes to the Nebraska server
+type testWrite struct {
+	Method string
+	Path   string
+	Body   map[string]any
+}
+
+// testStatus is a response of the Nebraska server of a testServer that
+// responds with the status code
+type testStatus int
+
+// newTestServer returns a provider server configured against a Nebraska
+// server that responds with the given responses by path, or by method and
+// path, like "PUT /api/apps"
+func newTestServer(t *testing.T, responses map[string]any) *testServer {
+	t.Helper()
+
+	var ts *testServer
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			write := testWrite{Method: r.Method, Path: r.URL.Path}
+			if err := json.NewDecoder(r.Body).Decode(&write.Body); err != nil {
+				t.Error(err)
+			}
+			ts.writes = append(ts.writes, write)
+		}
+		resp, ok := responses[r.Method+" "+r.URL.Path]
+		if !ok {
+			resp, ok = responses[r.URL.Path]
+		}
+		if !ok && r.URL.Path == "/config" {
+			resp, ok = map[string]string{"nebraska_version": nebraskatest.DefaultVersion}, true
+		}
+		if !ok && r.URL.Path == "/health" {
+			resp, ok = "OK", true
+		}
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if status, ok := resp.(testStatus); ok {
+			http.Error(w, http.StatusText(int(status)), int(status))
+			return
+		}
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			t.Error(err)
+		}
+	}))
+	t.Cleanup(s.Close)
+
+	ts = newTestProviderServer(t, s.URL)
+
+	return ts
+}
+
+// newFakeTestServer returns a provider server configured against a fake
+// Nebraska server
+func newFakeTestServer(t *testing.T) (*testServer, *nebraskatest.Server) {
+	t.Helper()
+
+	fake := nebraskatest.NewServer()
+	t.Cleanup(fake.Close)
+
+	return newTestProviderServer(t, fake.URL), fake
+}
+
+// newTestProviderServer returns a provider server configured against the
+// Nebraska server at the endpoint
+func newTestProviderServer(t *testing.T, endpoint string) *testServer {
+	t.Helper()
+
+	ts, diags := configureTestProviderServer(t, endpoint)
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	return ts
+}
+
+// configureTestProviderServer returns a provider server configured against
+// the Nebraska server at the endpoint and the diagnostics of configuring it
+func configureTestProviderServer(t *testing.T, endpoint string) (*testServer, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	ctx := context.Background()
+	providerServer, err := ProviderServer(ctx, "dev")
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ts := &testServer{ProviderServer: providerServer()}
+
+	ts.schema, err = ts.GetProviderSchema(ctx, &tfprotov5.GetProviderSchemaRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	for _, d := range ts.schema.Diagnostics {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	providerConfig := testDynamicValue(t, ts.schema.Provider, map[string]tftypes.Value{
+		"application_id": tftypes.NewValue(tftypes.String, nebraska.FlatcarApplicationID),
+		"endpoint":       tftypes.NewValue(tftypes.String, endpoint),
+	})
+	configureResp, err := ts.ConfigureProvider(ctx, &tfprotov5.ConfigureProviderRequest{Config: &providerConfig})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return ts, configureResp.Diagnostics
+}
+
+// testListResource lists resources of the given type with the given list
+// configuration, from a Nebraska server that serves the given responses by
+// path
+func testListResource(t *testing.T, typeName string, responses map[string]any, config map[string]tftypes.Value, includeResource bool) []testListResult {
+	t.Helper()
+
+	ctx := context.Background()
+	server := newTestServer(t, responses)
+
+	identitySchemaResp, err := server.GetResourceIdentitySchemas(ctx, &tfprotov5.GetResourceIdentitySchemasRequest{})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	listConfig := testDynamicValue(t, server.schema.ListResourceSchemas[typeName], config)
+	stream, err := server.ProviderServer.(tfprotov5.ListResourceServer).ListResource(ctx, &tfprotov5.ListResourceRequest{
+		TypeName:        typeName,
+		Config:          &listConfig,
+		IncludeResource: includeResource,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var results []testListResult
+	for result := range stream.Results {
+		for _, d := range result.Diagnostics {
+			t.Fatalf("%s: %s", d.Summary, d.Detail)
+		}
+		r := testListResult{
+			DisplayName: result.DisplayName,
+			Identity:    testAttributes(t, identitySchemaResp.IdentitySchemas[typeName].ValueType(), result.Identity.IdentityData),
+		}
+		if result.Resource != nil {
+			r.Resource = testAttributes(t, server.schema.ResourceSchemas[typeName].ValueType(), result.Resource)
+		}
+		results = append(results, r)
+	}
+
+	return results
+}
+
+// testInvokeAction invokes an action with the given configuration and returns
+// its progress messages and diagnostics
+func testInvokeAction(t *testing.T, server *testServer, typeName string, config map[string]tftypes.Value) ([]string, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	actionConfig := testDynamicValue(t, server.schema.ActionSchemas[typeName].Schema, config)
+	stream, err := server.ProviderServer.(tfprotov5.ActionServer).InvokeAction(context.Background(), &tfprotov5.InvokeActionRequest{
+		ActionType: typeName,
+		Config:     &actionConfig,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	var (
+		messages []string
+		diags    []*tfprotov5.Diagnostic
+	)
+	for event := range stream.Events {
+		switch e := event.Type.(type) {
+		case tfprotov5.ProgressInvokeActionEventType:
+			messages = append(messages, e.Message)
+		case tfprotov5.CompletedInvokeActionEventType:
+			diags = append(diags, e.Diagnostics...)
+		}
+	}
+
+	return messages, diags
+}
+
+// testApplyResource creates a resource of the given type with the given
+// configuration and returns its state and the diagnostics of the plan and
+// apply
+func testApplyResource(t *testing.T, server *testServer, typeName string, config map[string]tftypes.Value) (map[string]tftypes.Value, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	return testUpdateResource(t, server, typeName, nil, config)
+}
+
+// testUpdateResource updates a resource of the given type from the prior
+// state, or creates it if there isn't one, like testApplyResource
+func testUpdateResource(t *testing.T, server *testServer, typeName string, prior, config map[string]tftypes.Value) (map[string]tftypes.Value, []*tfprotov5.Diagnostic) {
+	t.Helper()
+
+	ctx := context.Background()
+	schema := server.schema.ResourceSchemas[typeName]
+	configValue := testDynamicValue(t, schema, config)
+	priorState, err := tfprotov5.NewDynamicValue(schema.ValueType(), tftypes.NewValue(schema.ValueType(), nil))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if prior != nil {
+		priorState = testDynamicValue(t, schema, prior)
+	}
+
+	planResp, err := server.PlanResourceChange(ctx, &tfprotov5.PlanResourceChangeRequest{
+		TypeName:         typeName,
+		PriorState:       &priorState,
+		ProposedNewState: &configValue,
+		Config:           &configValue,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diags := planResp.Diagnostics
+	for _, d := range diags {
+		if d.Severity == tfprotov5.DiagnosticSeverityError {
+			return nil, diags
+		}
+	}
+
+	applyResp, err := server.ApplyResourceChange(ctx, &tfprotov5.ApplyResourceChangeRequest{
+		TypeName:     typeName,
+		PriorState:   &priorState,
+		PlannedState: planResp.PlannedState,
+		Config:       &configValue,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	diags = append(diags, applyResp.Diagnostics...)
+
+	state := testAttributes(t, schema.ValueType(), applyResp.NewState)
+	if len(state) == 0 {
+		return nil, diags
+	}
+
+	return state, diags
+}
+
+// testListResult is a result of testListResource
+type testListResult struct {
+	DisplayName string
+	Identity    map[string]tftypes.Value
+	Resource    map[string]tftypes.Value
+}
+
+// testAttributes returns the attributes of an object value
+func testAttributes(t *testing.T, typ tftypes.Type, dv *tfprotov5.DynamicValue) map[string]tftypes.Value {
+	t.Helper()
+
+	v, err := dv.Unmarshal(typ)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	attrs := map[string]tftypes.Value{}
+	if err := v.As(&attrs); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return attrs
+}
+
+// testDynamicValue returns a value of the schema with the given attributes.
+// The other attributes are null.
+func testDynamicValue(t *testing.T, schema *tfprotov5.Schema, attrs map[string]tftypes.Value) tfprotov5.DynamicValue {
+	t.Helper()
+
+	typ := schema.ValueType().(tftypes.Object)
+	values := map[string]tftypes.Value{}
+	for name, attrType := range typ.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+		if v, ok := attrs[name]; ok {
+			values[name] = v
+		}
+	}
+
+	v, err := tfprotov5.NewDynamicValue(typ, tftypes.NewValue(typ, values))
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return v
 }
 
 func testAccPreCheck(t *testing.T) {
-	if os.Getenv("NEBRASKA_ENDPOINT") == "" {
+	mode, err := recorder.ModeFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode != recorder.ModeReplay && os.Getenv("NEBRASKA_ENDPOINT") == "" {
 		t.Fatal("NEBRASKA_ENDPOINT must be set for acceptance tests")
 	}
+	if mode != recorder.ModeLive {
+		testAccRecord(t, mode)
+	}
 
 	if os.Getenv("NEBRASKA_APPLICATION_ID") == "" {
 		if err := os.Setenv("NEBRASKA_APPLICATION_ID", nebraska.FlatcarApplicationID); err != nil {
@@ -34,3 +720,51 @@ func testAccPreCheck(t *testing.T) {
 		}
 	}
 }
+
+// testAccRecord points NEBRASKA_ENDPOINT at a server that records the
+// requests of the test into testdata/fixtures, or replays them without a
+// Nebraska server. The test fails if it hasn't been recorded.
+func testAccRecord(t *testing.T, mode recorder.Mode) {
+	t.Helper()
+
+	rec, err := recorder.New(filepath.Join("testdata", "fixtures", t.Name()+".json"), mode, http.DefaultTransport)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("%s hasn't been recorded, run it with NEBRASKA_TEST_MODE=record", t.Name())
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := rec.Server(os.Getenv("NEBRASKA_ENDPOINT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		s.Close()
+		if err := rec.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	t.Setenv("NEBRASKA_ENDPOINT", s.URL)
+}
+
+// testAccRandomName returns a name with testAccPrefix for the objects created
+// by the test. It's random when running against a Nebraska server, so that
+// runs don't conflict with each other or with objects left behind, and derived
+// from the name of the test when recording or replaying, so that the recorded
+// requests match.
+func testAccRandomName(t *testing.T) string {
+	if mode, err := recorder.ModeFromEnv(); err == nil && mode == recorder.ModeLive {
+		return acctest.RandomWithPrefix(testAccPrefix)
+	}
+	h := fnv.New32a()
+	h.Write([]byte(t.Name()))
+
+	return fmt.Sprintf("%s-%d", testAccPrefix, h.Sum32())
+}
+
+// testAccClient returns a client for the Nebraska server that the acceptance
+// tests run against
+func testAccClient() *nebraska.Client {
+	return nebraska.New(os.Getenv("NEBRASKA_ENDPOINT"), "terraform-provider-nebraska-test", os.Getenv("NEBRASKA_USERNAME"), os.Getenv("NEBRASKA_PASSWORD"), os.Getenv("NEBRASKA_BEARER_TOKEN"))
+}
@@ -2,174 +2,421 @@ package provider
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/kinvolk/nebraska/backend/pkg/api"
-	"github.com/kinvolk/nebraska/backend/pkg/codegen"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func resourceChannel() *schema.Resource {
-	return &schema.Resource{
-		Description: "A release channel that provides a particular package version.",
-
-		CreateContext: resourceChannelCreate,
-		ReadContext:   resourceChannelRead,
-		UpdateContext: resourceChannelUpdate,
-		DeleteContext: resourceChannelDelete,
-
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Name of the channel. Can be an existing one as long as the arch is different.",
+var (
+	_ resource.ResourceWithConfigure    = &channelResource{}
+	_ resource.ResourceWithIdentity     = &channelResource{}
+	_ resource.ResourceWithImportState  = &channelResource{}
+	_ resource.ResourceWithModifyPlan   = &channelResource{}
+	_ resource.ResourceWithUpgradeState = &channelResource{}
+)
+
+// NewChannelResource returns the nebraska_channel resource
+func NewChannelResource() resource.Resource {
+	return &channelResource{}
+}
+
+type channelResource struct {
+	client *apiClient
+}
+
+type channelResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	Name          types.String `tfsdk:"name"`
+	Arch          types.String `tfsdk:"arch"`
+	ApplicationID types.String `tfsdk:"application_id"`
+	Color         types.String `tfsdk:"color"`
+	CreatedTs     types.String `tfsdk:"created_ts"`
+	PackageID     types.String `tfsdk:"package_id"`
+	ForceDetach   types.Bool   `tfsdk:"force_detach"`
+	AdoptExisting types.Bool   `tfsdk:"adopt_existing"`
+}
+
+func (r *channelResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_channel"
+}
+
+func (r *channelResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A release channel that provides a particular package version.",
+		// Version 1 stores unset optional attributes as null rather than ""
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the channel.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of the channel. Can be an existing one as long as the arch is different.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"arch": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Arch. Cannot be changed once created.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"arch": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice([]string{"all", "amd64", "aarch64", "x86"}, false),
-				Description:  "Arch. Cannot be changed once created.",
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application this channel belongs to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"application_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Computed:    true,
-				Description: "ID of the application this channel belongs to.",
+			"color": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Hex color code that informs the color of the channel in the UI.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"color": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Hex color code that informs the color of the channel in the UI.",
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp.",
+			"package_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The id of the package this channel provides.",
 			},
-			"package_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The id of the package this channel provides.",
+			"force_detach": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "On delete, remove the channel from any groups that still provide it instead of failing. Defaults to `false`.",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "On create, take ownership of an existing channel with the same name and arch and update it to match the configuration, instead of failing. Defaults to the provider's `adopt_existing`.",
 			},
 		},
 	}
 }
 
-func resourceChannelCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *channelResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("channel")
+}
+
+func (r *channelResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *channelResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	resp.Diagnostics.Append(setProviderDefault(ctx, req.Config, &resp.Plan, path.Root("adopt_existing"), r.client.AdoptExisting)...)
+}
+
+func (r *channelResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan channelResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(plan.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create channel", err.Error())
+		return
+	}
+	plan.ApplicationID = types.StringValue(appID)
+	if plan.AdoptExisting.IsUnknown() {
+		plan.AdoptExisting = types.BoolValue(r.client.AdoptExisting)
 	}
-	d.Set("application_id", appID)
 
-	arch, err := api.ArchFromString(d.Get("arch").(string))
+	arch, err := nebraska.ArchFromString(plan.Arch.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create channel", err.Error())
+		return
+	}
+
+	if plan.AdoptExisting.ValueBool() {
+		channel, err := findChannel(r.client.WithContext(ctx), appID, plan.Name.ValueString(), plan.Arch.ValueString())
+		if err != nil && !errors.Is(err, nebraska.ErrNotFound) {
+			resp.Diagnostics.AddError("Couldn't create channel", err.Error())
+			return
+		}
+		if channel != nil {
+			plan.ID = types.StringValue(channel.ID)
+			// Keep the color when it's omitted
+			if plan.Color.IsUnknown() {
+				plan.Color = types.StringValue(channel.Color)
+			}
+			input := &nebraska.UpdateChannelInput{
+				Name:          plan.Name.ValueString(),
+				Color:         plan.Color.ValueString(),
+				PackageID:     plan.PackageID.ValueString(),
+				ApplicationID: appID,
+				Arch:          arch,
+			}
+			if _, err := r.client.WithContext(ctx).UpdateChannel(appID, channel.ID, input); err != nil {
+				resp.Diagnostics.AddError("Couldn't adopt channel", err.Error())
+				return
+			}
+
+			r.readInto(ctx, &plan, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
+			return
+		}
 	}
 
 	input := &nebraska.AddChannelInput{
-		Name:      d.Get("name").(string),
-		Color:     d.Get("color").(string),
-		PackageID: d.Get("package_id").(string),
-		Arch:      codegen.Arch(arch),
+		Name:      plan.Name.ValueString(),
+		Color:     plan.Color.ValueString(),
+		PackageID: plan.PackageID.ValueString(),
+		Arch:      arch,
 	}
 
-	channel, err := c.AddChannel(appID, input)
+	channel, err := r.client.WithContext(ctx).AddChannel(appID, input)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create channel", err.Error())
+		return
 	}
+	plan.ID = types.StringValue(channel.ID)
 
-	d.SetId(channel.Id)
-
-	return resourceChannelRead(ctx, d, meta)
+	r.readInto(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
 }
 
-func resourceChannelRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *channelResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state channelResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.readInto(ctx, &state, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, state.ApplicationID, state.ID)...)
+}
 
-	appID, err := getApplicationID(d, c)
+// readInto refreshes the model from Nebraska. It returns false when the
+// channel doesn't exist or can't be read.
+func (r *channelResource) readInto(ctx context.Context, m *channelResourceModel, diags *diag.Diagnostics) bool {
+	appID, err := applicationID(m.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		diags.AddError("Couldn't read channel", err.Error())
+		return false
 	}
-	d.Set("application_id", appID)
+	m.ApplicationID = types.StringValue(appID)
 
-	channel, err := c.GetChannel(appID, d.Id())
+	channel, err := r.client.WithContext(ctx).GetChannel(appID, m.ID.ValueString())
 	if err != nil {
 		if err == nebraska.ErrNotFound {
-			d.SetId("")
-			return nil
+			return false
 		}
-		return diag.FromErr(err)
-	}
-	if channel == nil {
-		d.SetId("")
-		return nil
+		diags.AddError("Couldn't read channel", err.Error())
+		return false
 	}
 
-	if err := d.Set("name", channel.Name); err != nil {
-		return diag.FromErr(err)
+	m.setChannel(channel)
+
+	return true
+}
+
+// setChannel sets the attributes of the model that are read from Nebraska
+func (m *channelResourceModel) setChannel(channel *nebraska.Channel) {
+	m.Name = types.StringValue(channel.Name)
+	m.Arch = types.StringValue(channel.Arch.String())
+	m.Color = types.StringValue(channel.Color)
+	m.CreatedTs = types.StringValue(channel.CreatedTs.String())
+	m.PackageID = optionalString(m.PackageID, channel.PackageID)
+}
+
+func (r *channelResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan channelResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := d.Set("color", channel.Color); err != nil {
-		return diag.FromErr(err)
+	appID, err := applicationID(plan.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update channel", err.Error())
+		return
+	}
+	arch, err := nebraska.ArchFromString(plan.Arch.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update channel", err.Error())
+		return
+	}
+	if plan.AdoptExisting.IsUnknown() {
+		plan.AdoptExisting = types.BoolValue(r.client.AdoptExisting)
 	}
 
-	if err := d.Set("created_ts", channel.CreatedTs.String()); err != nil {
-		return diag.FromErr(err)
+	input := &nebraska.UpdateChannelInput{
+		Name:          plan.Name.ValueString(),
+		Color:         plan.Color.ValueString(),
+		PackageID:     plan.PackageID.ValueString(),
+		ApplicationID: appID,
+		Arch:          arch,
 	}
 
-	if err := d.Set("package_id", channel.PackageID); err != nil {
-		return diag.FromErr(err)
+	if _, err := r.client.WithContext(ctx).UpdateChannel(appID, plan.ID.ValueString(), input); err != nil {
+		resp.Diagnostics.AddError("Couldn't update channel", err.Error())
+		return
 	}
 
-	return nil
+	r.readInto(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
 }
 
-func resourceChannelUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *channelResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state channelResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	appID, err := applicationID(state.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't delete channel", err.Error())
+		return
 	}
-	arch, err := api.ArchFromString(d.Get("arch").(string))
+	id := state.ID.ValueString()
+
+	groups, err := groupsWithChannel(r.client.WithContext(ctx).Client, appID, id)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't delete channel", err.Error())
+		return
 	}
-
-	input := &nebraska.UpdateChannelInput{
-		Name:          d.Get("name").(string),
-		Color:         d.Get("color").(string),
-		PackageID:     d.Get("package_id").(string),
-		ApplicationID: appID,
-		Arch:          codegen.Arch(arch),
+	if len(groups) > 0 {
+		if !state.ForceDetach.ValueBool() {
+			names := make([]string, 0, len(groups))
+			for _, g := range groups {
+				names = append(names, fmt.Sprintf("%q (%s)", g.Name, g.ID))
+			}
+			resp.Diagnostics.AddError("Channel is still in use", fmt.Sprintf("Channel %s is provided by the groups: %s. Point them at another channel first, or set force_detach to remove the channel from them.", id, strings.Join(names, ", ")))
+			return
+		}
+		for _, g := range groups {
+			input := updateGroupInputFromGroup(g)
+			input.ChannelID = ""
+			if _, err := r.client.WithContext(ctx).UpdateGroup(appID, g.ID, input); err != nil {
+				resp.Diagnostics.AddError("Couldn't delete channel", fmt.Sprintf("couldn't detach channel %s from group %s: %s", id, g.ID, err))
+				return
+			}
+		}
 	}
 
-	if _, err := c.UpdateChannel(appID, d.Id(), input); err != nil {
-		return diag.FromErr(err)
+	if err := r.client.WithContext(ctx).DeleteChannel(appID, id); err != nil {
+		resp.Diagnostics.AddError("Couldn't delete channel", err.Error())
 	}
+}
 
-	return resourceChannelRead(ctx, d, meta)
+func (r *channelResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importApplicationID(ctx, req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("force_detach"), false)...)
+	if r.client != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), r.client.AdoptExisting)...)
+	}
 }
 
-func resourceChannelDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *channelResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var s resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &s)
+
+	return map[int64]resource.StateUpgrader{
+		// State written by terraform-plugin-sdk
+		0: {
+			PriorSchema: &s.Schema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state channelResourceModel
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state.PackageID = nullIfEmpty(state.PackageID)
+				if state.ForceDetach.IsNull() {
+					state.ForceDetach = types.BoolValue(false)
+				}
+				if state.AdoptExisting.IsNull() {
+					state.AdoptExisting = types.BoolValue(r.client != nil && r.client.AdoptExisting)
+				}
 
-	appID, err := getApplicationID(d, c)
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
+	}
+}
+
+// groupsWithChannel returns the groups in the application that provide the
+// given channel. It takes the client without the read cache, so that deletes
+// are decided on what the server has now.
+func groupsWithChannel(c *nebraska.Client, appID, channelID string) ([]nebraska.Group, error) {
+	groupPage, err := c.ListGroups(appID)
 	if err != nil {
-		return diag.FromErr(err)
+		return nil, err
+	}
+	if groupPage.Count != groupPage.TotalCount {
+		return nil, fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount)
 	}
-	if err := c.DeleteChannel(appID, d.Id()); err != nil {
-		return diag.FromErr(err)
+
+	var groups []nebraska.Group
+	for _, g := range groupPage.Groups {
+		if g.ChannelID == channelID {
+			groups = append(groups, g)
+		}
 	}
 
-	return nil
+	return groups, nil
+}
+
+// updateChannelInputFromChannel returns the input that updates a channel to
+// its current settings
+func updateChannelInputFromChannel(appID string, ch nebraska.Channel) *nebraska.UpdateChannelInput {
+	return &nebraska.UpdateChannelInput{
+		Name:          ch.Name,
+		Color:         ch.Color,
+		PackageID:     ch.PackageID,
+		ApplicationID: appID,
+		Arch:          ch.Arch,
+	}
 }
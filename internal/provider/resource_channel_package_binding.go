@@ -0,0 +1,338 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/mapvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+var (
+	_ resource.ResourceWithConfigure   = &channelPackageBindingResource{}
+	_ resource.ResourceWithImportState = &channelPackageBindingResource{}
+	_ resource.ResourceWithModifyPlan  = &channelPackageBindingResource{}
+)
+
+// NewChannelPackageBindingResource returns the
+// nebraska_channel_package_binding resource
+func NewChannelPackageBindingResource() resource.Resource {
+	return &channelPackageBindingResource{}
+}
+
+type channelPackageBindingResource struct {
+	client *apiClient
+}
+
+type channelPackageBindingResourceModel struct {
+	ID            types.String `tfsdk:"id"`
+	ApplicationID types.String `tfsdk:"application_id"`
+	Bindings      types.Map    `tfsdk:"bindings"`
+}
+
+// channelPackageBinding is a channel and the package it should provide
+type channelPackageBinding struct {
+	channel *nebraska.Channel
+	pkg     *nebraska.Package
+}
+
+func (r *channelPackageBindingResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_channel_package_binding"
+}
+
+func (r *channelPackageBindingResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "Points several channels at their packages together, for example to promote a version across arches. " +
+			"If one of the channels can't be updated, the channels that were already updated are reverted to their previous packages. " +
+			"Don't set `package_id` on `nebraska_channel` resources for the same channels. " +
+			"Deleting the binding leaves the channels as they are.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "The ids of the channels the binding was created with, separated by commas.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application the channels and packages belong to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
+			},
+			"bindings": schema.MapAttribute{
+				Required:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "The id of the package each channel provides, by channel id. Each package must have the same arch as its channel and the channel mustn't be in its `channels_blacklist`.",
+				Validators: []validator.Map{
+					mapvalidator.SizeAtLeast(1),
+					mapvalidator.ValueStringsAre(stringvalidator.LengthAtLeast(1)),
+				},
+			},
+		},
+	}
+}
+
+func (r *channelPackageBindingResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
+
+// ModifyPlan checks the bindings when they're known, so that bindings that
+// can't be applied fail at plan time
+func (r *channelPackageBindingResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
+	}
+
+	var config channelPackageBindingResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() || config.ApplicationID.IsUnknown() || config.Bindings.IsUnknown() {
+		return
+	}
+	for _, v := range config.Bindings.Elements() {
+		if v.IsUnknown() {
+			return
+		}
+	}
+
+	appID, err := applicationID(config.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't bind packages", err.Error())
+		return
+	}
+	var bindings map[string]string
+	resp.Diagnostics.Append(config.Bindings.ElementsAs(ctx, &bindings, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	_, diags := r.resolve(ctx, appID, bindings)
+	resp.Diagnostics.Append(diags...)
+}
+
+func (r *channelPackageBindingResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan channelPackageBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(plan.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't bind packages", err.Error())
+		return
+	}
+	plan.ApplicationID = types.StringValue(appID)
+
+	var bindings map[string]string
+	resp.Diagnostics.Append(plan.Bindings.ElementsAs(ctx, &bindings, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.apply(ctx, appID, bindings, &resp.Diagnostics) {
+		return
+	}
+	channelIDs := make([]string, 0, len(bindings))
+	for channelID := range bindings {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+	plan.ID = types.StringValue(strings.Join(channelIDs, ","))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *channelPackageBindingResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state channelPackageBindingResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(state.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't read package bindings", err.Error())
+		return
+	}
+	state.ApplicationID = types.StringValue(appID)
+
+	// The bindings aren't known after an import, so the channels come from
+	// the id
+	var channelIDs []string
+	if state.Bindings.IsNull() {
+		channelIDs = strings.Split(state.ID.ValueString(), ",")
+	} else {
+		var bindings map[string]string
+		resp.Diagnostics.Append(state.Bindings.ElementsAs(ctx, &bindings, false)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		for channelID := range bindings {
+			channelIDs = append(channelIDs, channelID)
+		}
+	}
+
+	bindings := map[string]string{}
+	for _, channelID := range channelIDs {
+		channel, err := r.client.WithContext(ctx).GetChannel(appID, channelID)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				continue
+			}
+			resp.Diagnostics.AddError("Couldn't read package bindings", err.Error())
+			return
+		}
+		bindings[channelID] = channel.PackageID
+	}
+	if len(bindings) == 0 {
+		resp.State.RemoveResource(ctx)
+		return
+	}
+
+	m, diags := types.MapValueFrom(ctx, types.StringType, bindings)
+	resp.Diagnostics.Append(diags...)
+	state.Bindings = m
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+func (r *channelPackageBindingResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan channelPackageBindingResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(plan.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't bind packages", err.Error())
+		return
+	}
+
+	var bindings map[string]string
+	resp.Diagnostics.Append(plan.Bindings.ElementsAs(ctx, &bindings, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if !r.apply(ctx, appID, bindings, &resp.Diagnostics) {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *channelPackageBindingResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+}
+
+func (r *channelPackageBindingResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importApplicationID(ctx, req, resp)
+}
+
+// resolve returns the channels and packages of the bindings, or errors for
+// all the bindings that can't be applied
+func (r *channelPackageBindingResource) resolve(ctx context.Context, appID string, bindings map[string]string) ([]channelPackageBinding, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	channelIDs := make([]string, 0, len(bindings))
+	for channelID := range bindings {
+		channelIDs = append(channelIDs, channelID)
+	}
+	sort.Strings(channelIDs)
+
+	resolved := make([]channelPackageBinding, 0, len(bindings))
+	for _, channelID := range channelIDs {
+		packageID := bindings[channelID]
+
+		channel, err := r.client.WithContext(ctx).GetChannel(appID, channelID)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				err = fmt.Errorf("couldn't find channel %s", channelID)
+			}
+			diags.AddError("Couldn't bind packages", err.Error())
+			continue
+		}
+		pkg, err := r.client.WithContext(ctx).GetPackage(appID, packageID)
+		if err != nil {
+			if err == nebraska.ErrNotFound {
+				err = fmt.Errorf("couldn't find package %s", packageID)
+			}
+			diags.AddError("Couldn't bind packages", err.Error())
+			continue
+		}
+
+		if pkg.Arch != channel.Arch {
+			diags.AddError("Couldn't bind packages", fmt.Sprintf("package %s (%s) has a different arch to channel %q (%s)", pkg.Version, pkg.Arch.String(), channel.Name, channel.Arch.String()))
+			continue
+		}
+		if slices.Contains(pkg.ChannelsBlacklist, channelID) {
+			diags.AddError("Couldn't bind packages", fmt.Sprintf("channel %q (%s) is in the channels_blacklist of package %s", channel.Name, channelID, pkg.Version))
+			continue
+		}
+
+		resolved = append(resolved, channelPackageBinding{channel: channel, pkg: pkg})
+	}
+
+	return resolved, diags
+}
+
+// apply points the channels at their packages. If a channel can't be updated
+// then the channels that were already updated are reverted to their previous
+// packages. It returns false if the bindings weren't applied.
+func (r *channelPackageBindingResource) apply(ctx context.Context, appID string, bindings map[string]string, diags *diag.Diagnostics) bool {
+	resolved, d := r.resolve(ctx, appID, bindings)
+	diags.Append(d...)
+	if diags.HasError() {
+		return false
+	}
+
+	var updated []channelPackageBinding
+	for _, b := range resolved {
+		if b.channel.PackageID == b.pkg.ID {
+			continue
+		}
+
+		input := updateChannelInputFromChannel(appID, *b.channel)
+		input.PackageID = b.pkg.ID
+		if _, err := r.client.WithContext(ctx).UpdateChannel(appID, b.channel.ID, input); err != nil {
+			diags.AddError("Couldn't bind packages", fmt.Sprintf("couldn't point channel %q (%s) at package %s: %s", b.channel.Name, b.channel.ID, b.pkg.Version, err))
+			r.revert(ctx, appID, updated, diags)
+			return false
+		}
+		updated = append(updated, b)
+	}
+
+	return true
+}
+
+// revert points the channels back at the packages they provided before they
+// were updated
+func (r *channelPackageBindingResource) revert(ctx context.Context, appID string, updated []channelPackageBinding, diags *diag.Diagnostics) {
+	var reverted []string
+	for i := len(updated) - 1; i >= 0; i-- {
+		ch := updated[i].channel
+		if _, err := r.client.WithContext(ctx).UpdateChannel(appID, ch.ID, updateChannelInputFromChannel(appID, *ch)); err != nil {
+			diags.AddError("Couldn't revert channel", fmt.Sprintf("channel %q (%s) provides package %s instead of its previous package %q: %s", ch.Name, ch.ID, updated[i].pkg.Version, ch.PackageID, err))
+			continue
+		}
+		reverted = append(reverted, fmt.Sprintf("%q (%s)", ch.Name, ch.ID))
+	}
+	if len(reverted) > 0 {
+		diags.AddWarning("Reverted channels", fmt.Sprintf("The channels %s were reverted to their previous packages.", strings.Join(reverted, ", ")))
+	}
+}
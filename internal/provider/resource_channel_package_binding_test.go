@@ -0,0 +1,83 @@
+package provider
+
+import (
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func TestChannelPackageBindingResource(t *testing.T) {
+	appPath := "/api/apps/" + nebraska.FlatcarApplicationID
+	responses := func() map[string]any {
+		return map[string]any{
+			appPath + "/channels/c1": nebraska.Channel{ID: "c1", Name: "stable-amd64", Arch: nebraska.ArchAMD64, Color: "#000000", PackageID: "p1"},
+			appPath + "/channels/c2": nebraska.Channel{ID: "c2", Name: "stable-arm64", Arch: nebraska.ArchAArch64, Color: "#ffffff", PackageID: "p3"},
+			appPath + "/packages/p1": nebraska.Package{ID: "p1", Version: "3510.2.1", Arch: nebraska.ArchAMD64},
+			appPath + "/packages/p2": nebraska.Package{ID: "p2", Version: "3602.2.0", Arch: nebraska.ArchAMD64},
+			appPath + "/packages/p3": nebraska.Package{ID: "p3", Version: "3510.2.1", Arch: nebraska.ArchAArch64},
+			appPath + "/packages/p4": nebraska.Package{ID: "p4", Version: "3602.2.0", Arch: nebraska.ArchAArch64},
+		}
+	}
+	config := func(bindings map[string]string) map[string]tftypes.Value {
+		values := map[string]tftypes.Value{}
+		for channelID, packageID := range bindings {
+			values[channelID] = tftypes.NewValue(tftypes.String, packageID)
+		}
+		return map[string]tftypes.Value{
+			"bindings": tftypes.NewValue(tftypes.Map{ElementType: tftypes.String}, values),
+		}
+	}
+
+	t.Run("apply", func(t *testing.T) {
+		server := newTestServer(t, responses())
+		state, diags := testApplyResource(t, server, "nebraska_channel_package_binding", config(map[string]string{"c1": "p2", "c2": "p4"}))
+		assert.Equal(t, len(diags), 0)
+		assert.Assert(t, state["id"].Equal(tftypes.NewValue(tftypes.String, "c1,c2")))
+		assert.Assert(t, state["application_id"].Equal(tftypes.NewValue(tftypes.String, nebraska.FlatcarApplicationID)))
+		assert.Equal(t, len(server.writes), 2)
+		assert.Equal(t, server.writes[0].Path, appPath+"/channels/c1")
+		assert.Equal(t, server.writes[0].Body["package_id"], "p2")
+		assert.Equal(t, server.writes[0].Body["color"], "#000000")
+		assert.Equal(t, server.writes[1].Path, appPath+"/channels/c2")
+		assert.Equal(t, server.writes[1].Body["package_id"], "p4")
+	})
+
+	t.Run("unchanged channels aren't updated", func(t *testing.T) {
+		server := newTestServer(t, responses())
+		_, diags := testApplyResource(t, server, "nebraska_channel_package_binding", config(map[string]string{"c1": "p1", "c2": "p4"}))
+		assert.Equal(t, len(diags), 0)
+		assert.Equal(t, len(server.writes), 1)
+		assert.Equal(t, server.writes[0].Path, appPath+"/channels/c2")
+	})
+
+	t.Run("partial failure reverts", func(t *testing.T) {
+		r := responses()
+		r["PUT "+appPath+"/channels/c2"] = testStatus(500)
+		server := newTestServer(t, r)
+		state, diags := testApplyResource(t, server, "nebraska_channel_package_binding", config(map[string]string{"c1": "p2", "c2": "p4"}))
+		assert.Assert(t, state == nil)
+		assert.Equal(t, len(diags), 2)
+		assert.Equal(t, diags[0].Severity, tfprotov5.DiagnosticSeverityError)
+		assert.Equal(t, diags[0].Summary, "Couldn't bind packages")
+		assert.Equal(t, diags[1].Severity, tfprotov5.DiagnosticSeverityWarning)
+		assert.Equal(t, diags[1].Detail, `The channels "stable-amd64" (c1) were reverted to their previous packages.`)
+		assert.Equal(t, len(server.writes), 3)
+		assert.Equal(t, server.writes[0].Path, appPath+"/channels/c1")
+		assert.Equal(t, server.writes[0].Body["package_id"], "p2")
+		assert.Equal(t, server.writes[1].Path, appPath+"/channels/c2")
+		assert.Equal(t, server.writes[1].Body["package_id"], "p4")
+		assert.Equal(t, server.writes[2].Path, appPath+"/channels/c1")
+		assert.Equal(t, server.writes[2].Body["package_id"], "p1")
+	})
+
+	t.Run("arch mismatch fails at plan time", func(t *testing.T) {
+		server := newTestServer(t, responses())
+		_, diags := testApplyResource(t, server, "nebraska_channel_package_binding", config(map[string]string{"c1": "p2", "c2": "p2"}))
+		assert.Equal(t, len(diags), 1)
+		assert.Equal(t, diags[0].Detail, `package 3602.2.0 (amd64) has a different arch to channel "stable-arm64" (aarch64)`)
+		assert.Equal(t, len(server.writes), 0)
+	})
+}
@@ -1,25 +1,32 @@
 package provider
 
 import (
+	"fmt"
+	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
 )
 
 func TestAccChannelResource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "nebraska_channel.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceChannel,
+				Config: fmt.Sprintf(testAccResourceChannel, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
-					resource.TestCheckResourceAttr(dsn, "name", "test-terraform"),
+					resource.TestCheckResourceAttr(dsn, "name", rName),
 					resource.TestCheckResourceAttr(dsn, "arch", "amd64"),
 					resource.TestCheckResourceAttrSet(dsn, "package_id"),
 					resource.TestCheckResourceAttr(dsn, "color", "#1fbb86"),
@@ -27,6 +34,11 @@ func TestAccChannelResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
 				),
 			},
+			{
+				ResourceName:      dsn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -36,15 +48,246 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test" {
-  version = "0.0.0"
+  version = "0.0.0-%[1]s"
   arch    = "amd64"
   url     = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "test-terraform"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test.id
   color      = "#1fbb86"
 }
 `
+
+func TestAccChannelResource_forceDetach(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_channel.test"
+	var groupID string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			c := testAccClient()
+			appID := os.Getenv("NEBRASKA_APPLICATION_ID")
+			group, err := c.GetGroup(appID, groupID)
+			if err != nil {
+				return err
+			}
+			if group.ChannelID != "" {
+				return fmt.Errorf("group %s still provides channel %s", groupID, group.ChannelID)
+			}
+
+			return c.DeleteGroup(appID, groupID)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceChannelForceDetach, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "force_detach", "true"),
+					// Add a group that isn't managed by terraform
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources[dsn]
+						group, err := testAccClient().AddGroup(rs.Primary.Attributes["application_id"], &nebraska.AddGroupInput{
+							Name:                      rName,
+							ChannelID:                 rs.Primary.ID,
+							PolicyPeriodInterval:      "1 minutes",
+							PolicyMaxUpdatesPerPeriod: 1,
+							PolicyUpdateTimeout:       "60 minutes",
+						})
+						if err != nil {
+							return err
+						}
+						groupID = group.ID
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceChannelForceDetach = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version = "0.0.0-%[1]s"
+  arch    = "amd64"
+  url     = "http://fake-address/"
+}
+
+resource "nebraska_channel" "test" {
+  name         = "%[1]s"
+  arch         = "amd64"
+  package_id   = nebraska_package.test.id
+  force_detach = true
+}
+`
+
+func TestAccChannelResource_adoptExisting(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_channel.test"
+	var channelID string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// Add a channel that isn't managed by terraform
+			channel, err := testAccClient().AddChannel(os.Getenv("NEBRASKA_APPLICATION_ID"), &nebraska.AddChannelInput{
+				Name:  rName,
+				Color: "#000000",
+				Arch:  nebraska.ArchAMD64,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			channelID = channel.ID
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceChannelAdoptExisting, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "adopt_existing", "true"),
+					resource.TestCheckResourceAttrPtr(dsn, "id", &channelID),
+					resource.TestCheckResourceAttr(dsn, "color", "#1fbb86"),
+					resource.TestCheckResourceAttrPair(dsn, "package_id", "nebraska_package.test", "id"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceChannelAdoptExisting = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version = "0.0.3-%[1]s"
+  arch    = "amd64"
+  url     = "http://fake-address/"
+}
+
+resource "nebraska_channel" "test" {
+  name           = "%[1]s"
+  arch           = "amd64"
+  package_id     = nebraska_package.test.id
+  adopt_existing = true
+}
+`
+
+func TestChannelResource(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	pkg, err := fake.Client().AddPackage(nebraska.FlatcarApplicationID, &nebraska.AddPackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{},
+		Type:              nebraska.PackageTypeFlatcar,
+		Version:           "3602.2.0",
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, diags := testApplyResource(t, server, "nebraska_channel", map[string]tftypes.Value{
+		"name":       tftypes.NewValue(tftypes.String, "stable"),
+		"arch":       tftypes.NewValue(tftypes.String, "amd64"),
+		"package_id": tftypes.NewValue(tftypes.String, pkg.ID),
+		"color":      tftypes.NewValue(tftypes.String, "#1fbb86"),
+	})
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	var id string
+	if err := state["id"].As(&id); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	ch, err := fake.Client().GetChannel(nebraska.FlatcarApplicationID, id)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if ch.Name != "stable" || ch.PackageID != pkg.ID {
+		t.Errorf("got channel %q with package %q, want \"stable\" with package %q", ch.Name, ch.PackageID, pkg.ID)
+	}
+
+	// Nebraska rejects a package with a different arch
+	_, diags = testApplyResource(t, server, "nebraska_channel", map[string]tftypes.Value{
+		"name":       tftypes.NewValue(tftypes.String, "stable"),
+		"arch":       tftypes.NewValue(tftypes.String, "aarch64"),
+		"package_id": tftypes.NewValue(tftypes.String, pkg.ID),
+	})
+	if len(diags) == 0 {
+		t.Error("expected an error creating a channel with a package with a different arch")
+	}
+}
+
+func TestChannelResourceUpgradeStateV0(t *testing.T) {
+	// State written by terraform-plugin-sdk before force_detach and
+	// adopt_existing were added
+	attrs := testUpgradeResourceState(t, "nebraska_channel", `{
+  "id": "5b810680-e36a-4879-b98a-4f989e80b899",
+  "name": "stable",
+  "arch": "amd64",
+  "application_id": "e96281a6-d1af-4bde-9a0a-97b76e56dc57",
+  "color": "#1fbb86",
+  "created_ts": "2021-01-02 03:04:05 +0000 UTC",
+  "package_id": ""
+}`)
+
+	want := map[string]tftypes.Value{
+		"id":             tftypes.NewValue(tftypes.String, "5b810680-e36a-4879-b98a-4f989e80b899"),
+		"name":           tftypes.NewValue(tftypes.String, "stable"),
+		"arch":           tftypes.NewValue(tftypes.String, "amd64"),
+		"application_id": tftypes.NewValue(tftypes.String, "e96281a6-d1af-4bde-9a0a-97b76e56dc57"),
+		"color":          tftypes.NewValue(tftypes.String, "#1fbb86"),
+		"created_ts":     tftypes.NewValue(tftypes.String, "2021-01-02 03:04:05 +0000 UTC"),
+		"package_id":     tftypes.NewValue(tftypes.String, nil),
+		"force_detach":   tftypes.NewValue(tftypes.Bool, false),
+		"adopt_existing": tftypes.NewValue(tftypes.Bool, false),
+	}
+	for k, v := range want {
+		if !attrs[k].Equal(v) {
+			t.Errorf("%s: got %s, want %s", k, attrs[k], v)
+		}
+	}
+}
+
+func TestChannelResourceUpgradeFromSDKState(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	ids := testSDKStateObjects(t, fake)
+
+	changed := testPlanSDKState(t, server, "nebraska_channel", "nebraska_channel", ids, map[string]tftypes.Value{
+		"name":       tftypes.NewValue(tftypes.String, "stable"),
+		"arch":       tftypes.NewValue(tftypes.String, "amd64"),
+		"color":      tftypes.NewValue(tftypes.String, "#1fbb86"),
+		"package_id": tftypes.NewValue(tftypes.String, ids["9376a543-d590-4838-b768-84eda3ce93fd"]),
+	})
+	assert.DeepEqual(t, changed, []string(nil))
+}
+
+func TestAccChannelResource_upgradeFromSDK(t *testing.T) {
+	rName := testAccRandomName(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: sdkProvider,
+				Config:            fmt.Sprintf(testAccResourceChannel, rName),
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories,
+				Config:                   fmt.Sprintf(testAccResourceChannel, rName),
+				PlanOnly:                 true,
+			},
+		},
+	})
+}
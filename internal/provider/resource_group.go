@@ -2,256 +2,634 @@ package provider
 
 import (
 	"context"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64default"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func resourceGroup() *schema.Resource {
-	return &schema.Resource{
-		Description: "A group provides a particular release channel to machines and controls various options that manage the update procedure.",
-
-		CreateContext: resourceGroupCreate,
-		ReadContext:   resourceGroupRead,
-		UpdateContext: resourceGroupUpdate,
-		DeleteContext: resourceGroupDelete,
-
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Name of the group.",
+var (
+	_ resource.ResourceWithConfigure    = &groupResource{}
+	_ resource.ResourceWithIdentity     = &groupResource{}
+	_ resource.ResourceWithImportState  = &groupResource{}
+	_ resource.ResourceWithModifyPlan   = &groupResource{}
+	_ resource.ResourceWithUpgradeState = &groupResource{}
+)
+
+// uncountedMachineID matches the machine ids that Nebraska leaves out of the
+// instance stats of groups, so that update checks don't count as active
+// instances when the group is deleted
+var uncountedMachineID = regexp.MustCompile(`^\{.{8}-.{4}-.{4}-.{4}-.{12}\}$`)
+
+// NewGroupResource returns the nebraska_group resource
+func NewGroupResource() resource.Resource {
+	return &groupResource{}
+}
+
+type groupResource struct {
+	client *apiClient
+}
+
+type groupResourceModel struct {
+	ID                             types.String             `tfsdk:"id"`
+	Name                           types.String             `tfsdk:"name"`
+	Track                          types.String             `tfsdk:"track"`
+	ApplicationID                  types.String             `tfsdk:"application_id"`
+	Description                    types.String             `tfsdk:"description"`
+	CreatedTs                      types.String             `tfsdk:"created_ts"`
+	RolloutInProgress              types.Bool               `tfsdk:"rollout_in_progress"`
+	ChannelID                      types.String             `tfsdk:"channel_id"`
+	PolicyUpdatesEnabled           types.Bool               `tfsdk:"policy_updates_enabled"`
+	PolicySafeMode                 types.Bool               `tfsdk:"policy_safe_mode"`
+	PolicyOfficeHours              types.Bool               `tfsdk:"policy_office_hours"`
+	PolicyTimezone                 types.String             `tfsdk:"policy_timezone"`
+	PolicyPeriodInterval           types.String             `tfsdk:"policy_period_interval"`
+	PolicyMaxUpdatesPerPeriod      types.Int64              `tfsdk:"policy_max_updates_per_period"`
+	PolicyUpdateTimeout            types.String             `tfsdk:"policy_update_timeout"`
+	DeletionProtection             types.Bool               `tfsdk:"deletion_protection"`
+	AdoptExisting                  types.Bool               `tfsdk:"adopt_existing"`
+	ActiveInstancesWindow          types.String             `tfsdk:"active_instances_window"`
+	AllowDeleteWithActiveInstances types.Bool               `tfsdk:"allow_delete_with_active_instances"`
+	VerifyUpdateCheck              []verifyUpdateCheckModel `tfsdk:"verify_update_check"`
+}
+
+type verifyUpdateCheckModel struct {
+	FromVersion       types.String `tfsdk:"from_version"`
+	Arch              types.String `tfsdk:"arch"`
+	ExpectedPackageID types.String `tfsdk:"expected_package_id"`
+	MachineID         types.String `tfsdk:"machine_id"`
+	OnFailure         types.String `tfsdk:"on_failure"`
+}
+
+func (r *groupResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_group"
+}
+
+func (r *groupResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A group provides a particular release channel to machines and controls various options that manage the update procedure.",
+		// Version 1 stores unset optional attributes as null rather than ""
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the group.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Name of the group.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"track": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Identifier for clients, filled with the group ID if omitted.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"track": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Identifier for clients, filled with the group ID if omitted.",
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application this group belongs to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"application_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Computed:    true,
-				Description: "ID of the application this group belongs to.",
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A description of the group.",
 			},
-			"description": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "A description of the group.",
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp",
+			"rollout_in_progress": schema.BoolAttribute{
+				Computed:            true,
+				MarkdownDescription: "Indicates whether a rollout is currently in progress for this group.",
 			},
-			"rollout_in_progress": {
-				Type:        schema.TypeBool,
-				Computed:    true,
-				Description: "Indicates whether a rollout is currently in progress for this group.",
+			"channel_id": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The channel this group provides.",
 			},
-			"channel_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The channel this group provides.",
+			"policy_updates_enabled": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(true),
+				MarkdownDescription: "Enable updates. Defaults to `true`.",
 			},
-			"policy_updates_enabled": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     true,
-				Description: "Enable updates.",
+			"policy_safe_mode": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Safe mode will only update 1 instance at a time, and stop if an update fails. Defaults to `false`.",
 			},
-			"policy_safe_mode": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Safe mode will only update 1 instance at a time, and stop if an update fails.",
+			"policy_office_hours": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Only update between 9am and 5pm. Defaults to `false`.",
 			},
-			"policy_office_hours": {
-				Type:        schema.TypeBool,
-				Optional:    true,
-				Default:     false,
-				Description: "Only update between 9am and 5pm.",
+			"policy_timezone": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Timezone used to inform `policy_office_hours`.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"policy_timezone": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Computed:    true,
-				Description: "Timezone used to inform `policy_office_hours`.",
+			"policy_period_interval": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("1 minutes"),
+				MarkdownDescription: "Period used in combination with `policy_max_updates_per_period`. Defaults to `1 minutes`.",
 			},
-			"policy_period_interval": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "1 minutes",
-				Description: "Period used in combination with `policy_max_updates_per_period`.",
+			"policy_max_updates_per_period": schema.Int64Attribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             int64default.StaticInt64(9999999),
+				MarkdownDescription: "The maximum number of updates that can be performed within the `policy_period_interval`. Defaults to `9999999`.",
 			},
-			"policy_max_updates_per_period": {
-				Type:        schema.TypeInt,
-				Optional:    true,
-				Default:     9999999,
-				Description: "The maximum number of updates that can be performed within the `policy_period_interval`.",
+			"policy_update_timeout": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("60 minutes"),
+				MarkdownDescription: "Timeout for updates. Defaults to `60 minutes`.",
 			},
-			"policy_update_timeout": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Default:     "60 minutes",
-				Description: "Timeout for updates",
+			"deletion_protection": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "Refuse to delete the group. Defaults to the provider's `deletion_protection`.",
+			},
+			"adopt_existing": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "On create, take ownership of an existing group with the same name (and track, if set) and update it to match the configuration, instead of creating a new one. Defaults to the provider's `adopt_existing`.",
+			},
+			"active_instances_window": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("7d"),
+				MarkdownDescription: "Refuse to delete the group if any instances have checked for updates within this window: one of `1h`, `1d`, `7d` or `30d`. Defaults to `7d`. Setting it requires Nebraska >= 2.8.0, older releases use their own window.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("1h", "1d", "7d", "30d"),
+				},
+			},
+			"allow_delete_with_active_instances": schema.BoolAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             booldefault.StaticBool(false),
+				MarkdownDescription: "Delete the group even if it has active instances. Defaults to `false`.",
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"verify_update_check": schema.ListNestedBlock{
+				MarkdownDescription: "After every create and update, perform an Omaha update check as a machine on the group's track would and report whether the expected package is offered. Note that Nebraska registers the machine as an instance of the group.",
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"from_version": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "Version the machine is running when it checks for an update.",
+							Validators: []validator.String{
+								stringvalidator.LengthAtLeast(1),
+							},
+						},
+						"arch": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString("amd64"),
+							MarkdownDescription: "Arch of the machine. Defaults to `amd64`.",
+							Validators: []validator.String{
+								stringvalidator.OneOf("amd64", "aarch64", "x86"),
+							},
+						},
+						"expected_package_id": schema.StringAttribute{
+							Optional:            true,
+							MarkdownDescription: "The package that should be offered. Defaults to the package of the group's channel.",
+						},
+						"machine_id": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString(defaultUpdateCheckMachineID),
+							MarkdownDescription: fmt.Sprintf("Machine id reported to Nebraska, in braces like `%s`, which keeps the update check out of the group's instance stats. Defaults to `%s`.", defaultUpdateCheckMachineID, defaultUpdateCheckMachineID),
+							Validators: []validator.String{
+								stringvalidator.RegexMatches(uncountedMachineID, "must be a UUID in braces, which Nebraska leaves out of the group's instance stats"),
+							},
+						},
+						"on_failure": schema.StringAttribute{
+							Optional:            true,
+							Computed:            true,
+							Default:             stringdefault.StaticString("warn"),
+							MarkdownDescription: "Whether to `warn` or `fail` when the expected package is not offered. Defaults to `warn`.",
+							Validators: []validator.String{
+								stringvalidator.OneOf("warn", "fail"),
+							},
+						},
+					},
+				},
 			},
 		},
 	}
 }
 
-func resourceGroupCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *groupResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("group")
+}
 
-	appID, err := getApplicationID(d, c)
-	if err != nil {
-		return diag.FromErr(err)
-	}
-	d.Set("application_id", appID)
+func (r *groupResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
 
-	input := &nebraska.AddGroupInput{
-		Name:                      d.Get("name").(string),
-		Description:               d.Get("description").(string),
-		ChannelID:                 d.Get("channel_id").(string),
-		PolicyUpdatesEnabled:      d.Get("policy_updates_enabled").(bool),
-		PolicySafeMode:            d.Get("policy_safe_mode").(bool),
-		PolicyOfficeHours:         d.Get("policy_office_hours").(bool),
-		PolicyTimezone:            d.Get("policy_timezone").(string),
-		PolicyPeriodInterval:      d.Get("policy_period_interval").(string),
-		PolicyMaxUpdatesPerPeriod: d.Get("policy_max_updates_per_period").(int),
-		PolicyUpdateTimeout:       d.Get("policy_update_timeout").(string),
-		Track:                     d.Get("track").(string),
-	}
-
-	group, err := c.AddGroup(appID, input)
-	if err != nil {
-		return diag.FromErr(err)
+func (r *groupResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() || r.client == nil {
+		return
 	}
 
-	d.SetId(group.Id)
+	var window types.String
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("active_instances_window"), &window)...)
+	if !window.IsNull() {
+		r.client.requireFeature(nebraska.FeatureInstanceStatsDuration, path.Root("active_instances_window"), &resp.Diagnostics)
+	}
 
-	return resourceGroupRead(ctx, d, meta)
+	resp.Diagnostics.Append(setProviderDefault(ctx, req.Config, &resp.Plan, path.Root("deletion_protection"), r.client.DeletionProtection)...)
+	resp.Diagnostics.Append(setProviderDefault(ctx, req.Config, &resp.Plan, path.Root("adopt_existing"), r.client.AdoptExisting)...)
 }
 
-func resourceGroupRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *groupResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan groupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	appID, err := applicationID(plan.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create group", err.Error())
+		return
 	}
-	d.Set("application_id", appID)
-	group, err := c.GetGroup(appID, d.Id())
-	if err != nil {
-		if err == nebraska.ErrNotFound {
-			d.SetId("")
-			return nil
+	plan.ApplicationID = types.StringValue(appID)
+	r.setClientDefaults(&plan)
+
+	if plan.AdoptExisting.ValueBool() {
+		group, err := findGroup(r.client.WithContext(ctx), appID, plan.Name.ValueString(), plan.Track.ValueString())
+		if err != nil && !errors.Is(err, nebraska.ErrNotFound) {
+			resp.Diagnostics.AddError("Couldn't create group", err.Error())
+			return
+		}
+		if group != nil {
+			plan.ID = types.StringValue(group.ID)
+			// Keep the settings that Nebraska fills in when they're omitted
+			if plan.Track.ValueString() == "" {
+				plan.Track = types.StringValue(group.Track)
+			}
+			if plan.PolicyTimezone.ValueString() == "" {
+				plan.PolicyTimezone = types.StringValue(group.PolicyTimezone)
+			}
+
+			r.update(ctx, &plan, appID, &resp.Diagnostics)
+			if resp.Diagnostics.HasError() {
+				return
+			}
+			resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+			resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
+			return
 		}
-		return diag.FromErr(err)
-	}
-	if group == nil {
-		d.SetId("")
-		return nil
 	}
 
-	if err := d.Set("name", group.Name); err != nil {
-		return diag.FromErr(err)
+	input := &nebraska.AddGroupInput{
+		Name:                      plan.Name.ValueString(),
+		Description:               plan.Description.ValueString(),
+		ChannelID:                 plan.ChannelID.ValueString(),
+		PolicyUpdatesEnabled:      plan.PolicyUpdatesEnabled.ValueBool(),
+		PolicySafeMode:            plan.PolicySafeMode.ValueBool(),
+		PolicyOfficeHours:         plan.PolicyOfficeHours.ValueBool(),
+		PolicyTimezone:            plan.PolicyTimezone.ValueString(),
+		PolicyPeriodInterval:      plan.PolicyPeriodInterval.ValueString(),
+		PolicyMaxUpdatesPerPeriod: int(plan.PolicyMaxUpdatesPerPeriod.ValueInt64()),
+		PolicyUpdateTimeout:       plan.PolicyUpdateTimeout.ValueString(),
+		Track:                     plan.Track.ValueString(),
 	}
 
-	if err := d.Set("description", group.Description); err != nil {
-		return diag.FromErr(err)
+	group, err := r.client.WithContext(ctx).AddGroup(appID, input)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't create group", err.Error())
+		return
 	}
+	plan.ID = types.StringValue(group.ID)
 
-	if err := d.Set("created_ts", group.CreatedTs.String()); err != nil {
-		return diag.FromErr(err)
+	if r.readInto(ctx, &plan, &resp.Diagnostics) {
+		resp.Diagnostics.Append(verifyGroupUpdateCheck(r.client.WithContext(ctx), appID, plan)...)
 	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
+}
 
-	if err := d.Set("rollout_in_progress", group.RolloutInProgress); err != nil {
-		return diag.FromErr(err)
+func (r *groupResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state groupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := d.Set("channel_id", group.ChannelID); err != nil {
-		return diag.FromErr(err)
+	if !r.readInto(ctx, &state, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
+		}
+		return
 	}
 
-	if err := d.Set("policy_updates_enabled", group.PolicyUpdatesEnabled); err != nil {
-		return diag.FromErr(err)
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, state.ApplicationID, state.ID)...)
+}
+
+// readInto refreshes the model from Nebraska. It returns false when the group
+// doesn't exist or can't be read.
+func (r *groupResource) readInto(ctx context.Context, m *groupResourceModel, diags *diag.Diagnostics) bool {
+	appID, err := applicationID(m.ApplicationID, r.client)
+	if err != nil {
+		diags.AddError("Couldn't read group", err.Error())
+		return false
 	}
+	m.ApplicationID = types.StringValue(appID)
 
-	if err := d.Set("policy_safe_mode", group.PolicySafeMode); err != nil {
-		return diag.FromErr(err)
+	group, err := r.client.WithContext(ctx).GetGroup(appID, m.ID.ValueString())
+	if err != nil {
+		if err == nebraska.ErrNotFound {
+			return false
+		}
+		diags.AddError("Couldn't read group", err.Error())
+		return false
 	}
 
-	if err := d.Set("policy_office_hours", group.PolicyOfficeHours); err != nil {
-		return diag.FromErr(err)
+	m.setGroup(group)
+
+	return true
+}
+
+// setGroup sets the attributes of the model that are read from Nebraska
+func (m *groupResourceModel) setGroup(group *nebraska.Group) {
+	m.Name = types.StringValue(group.Name)
+	m.Description = optionalString(m.Description, group.Description)
+	m.CreatedTs = types.StringValue(group.CreatedTs.String())
+	m.RolloutInProgress = types.BoolValue(group.RolloutInProgress)
+	m.ChannelID = optionalString(m.ChannelID, group.ChannelID)
+	m.PolicyUpdatesEnabled = types.BoolValue(group.PolicyUpdatesEnabled)
+	m.PolicySafeMode = types.BoolValue(group.PolicySafeMode)
+	m.PolicyOfficeHours = types.BoolValue(group.PolicyOfficeHours)
+	m.PolicyTimezone = types.StringValue(group.PolicyTimezone)
+	m.PolicyPeriodInterval = types.StringValue(group.PolicyPeriodInterval)
+	m.PolicyMaxUpdatesPerPeriod = types.Int64Value(int64(group.PolicyMaxUpdatesPerPeriod))
+	m.PolicyUpdateTimeout = types.StringValue(group.PolicyUpdateTimeout)
+	m.Track = types.StringValue(group.Track)
+	if m.VerifyUpdateCheck == nil {
+		m.VerifyUpdateCheck = []verifyUpdateCheckModel{}
 	}
+}
 
-	if err := d.Set("policy_timezone", group.PolicyTimezone); err != nil {
-		return diag.FromErr(err)
+func (r *groupResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan groupResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := d.Set("policy_period_interval", group.PolicyPeriodInterval); err != nil {
-		return diag.FromErr(err)
+	appID, err := applicationID(plan.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update group", err.Error())
+		return
 	}
+	plan.ApplicationID = types.StringValue(appID)
+	r.setClientDefaults(&plan)
 
-	if err := d.Set("policy_max_updates_per_period", group.PolicyMaxUpdatesPerPeriod); err != nil {
-		return diag.FromErr(err)
+	r.update(ctx, &plan, appID, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
+}
+
+// update updates the group to match the model, refreshes the model and
+// verifies the update check
+func (r *groupResource) update(ctx context.Context, m *groupResourceModel, appID string, diags *diag.Diagnostics) {
+	input := &nebraska.UpdateGroupInput{
+		Name:                      m.Name.ValueString(),
+		Description:               m.Description.ValueString(),
+		ChannelID:                 m.ChannelID.ValueString(),
+		PolicyUpdatesEnabled:      m.PolicyUpdatesEnabled.ValueBool(),
+		PolicySafeMode:            m.PolicySafeMode.ValueBool(),
+		PolicyOfficeHours:         m.PolicyOfficeHours.ValueBool(),
+		PolicyTimezone:            m.PolicyTimezone.ValueString(),
+		PolicyPeriodInterval:      m.PolicyPeriodInterval.ValueString(),
+		PolicyMaxUpdatesPerPeriod: int(m.PolicyMaxUpdatesPerPeriod.ValueInt64()),
+		PolicyUpdateTimeout:       m.PolicyUpdateTimeout.ValueString(),
+		Track:                     m.Track.ValueString(),
 	}
 
-	if err := d.Set("policy_update_timeout", group.PolicyUpdateTimeout); err != nil {
-		return diag.FromErr(err)
+	if _, err := r.client.WithContext(ctx).UpdateGroup(appID, m.ID.ValueString(), input); err != nil {
+		diags.AddError("Couldn't update group", err.Error())
+		return
 	}
 
-	if err := d.Set("track", group.Track); err != nil {
-		return diag.FromErr(err)
+	if r.readInto(ctx, m, diags) {
+		diags.Append(verifyGroupUpdateCheck(r.client.WithContext(ctx), appID, *m)...)
 	}
+}
 
-	return nil
+// setClientDefaults fills in the provider's defaults when the plan couldn't
+func (r *groupResource) setClientDefaults(m *groupResourceModel) {
+	if m.DeletionProtection.IsUnknown() {
+		m.DeletionProtection = types.BoolValue(r.client.DeletionProtection)
+	}
+	if m.AdoptExisting.IsUnknown() {
+		m.AdoptExisting = types.BoolValue(r.client.AdoptExisting)
+	}
 }
 
-func resourceGroupUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *groupResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state groupResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	appID, err := applicationID(state.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't delete group", err.Error())
+		return
 	}
-	d.Set("application_id", appID)
+	id := state.ID.ValueString()
 
-	input := &nebraska.UpdateGroupInput{
-		Name:                      d.Get("name").(string),
-		Description:               d.Get("description").(string),
-		ChannelID:                 d.Get("channel_id").(string),
-		PolicyUpdatesEnabled:      d.Get("policy_updates_enabled").(bool),
-		PolicySafeMode:            d.Get("policy_safe_mode").(bool),
-		PolicyOfficeHours:         d.Get("policy_office_hours").(bool),
-		PolicyTimezone:            d.Get("policy_timezone").(string),
-		PolicyPeriodInterval:      d.Get("policy_period_interval").(string),
-		PolicyMaxUpdatesPerPeriod: d.Get("policy_max_updates_per_period").(int),
-		PolicyUpdateTimeout:       d.Get("policy_update_timeout").(string),
-		Track:                     d.Get("track").(string),
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError("Group is protected from deletion", fmt.Sprintf("Group %s has deletion_protection enabled. Set it to false and apply before deleting the group.", id))
+		return
+	}
+	if !state.AllowDeleteWithActiveInstances.ValueBool() {
+		window := state.ActiveInstancesWindow.ValueString()
+		stats, err := r.client.WithContext(ctx).GetGroupInstanceStats(appID, id, window)
+		if err != nil && err != nebraska.ErrNotFound {
+			resp.Diagnostics.AddError("Couldn't delete group", err.Error())
+			return
+		}
+		if stats != nil && stats.Total > 0 {
+			resp.Diagnostics.AddError("Group has active instances", fmt.Sprintf("%d instances of group %s have checked for updates in the last %s. Move them to another group, or set allow_delete_with_active_instances to delete the group anyway.", stats.Total, id, window))
+			return
+		}
+	}
+	if err := r.client.WithContext(ctx).DeleteGroup(appID, id); err != nil {
+		resp.Diagnostics.AddError("Couldn't delete group", err.Error())
 	}
+}
 
-	if _, err := c.UpdateGroup(appID, d.Id(), input); err != nil {
-		return diag.FromErr(err)
+func (r *groupResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importApplicationID(ctx, req, resp)
+	if r.client != nil {
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("deletion_protection"), r.client.DeletionProtection)...)
+		resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("adopt_existing"), r.client.AdoptExisting)...)
+	}
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("active_instances_window"), "7d")...)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("allow_delete_with_active_instances"), false)...)
+}
+
+func (r *groupResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var s resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &s)
+
+	return map[int64]resource.StateUpgrader{
+		// State written by terraform-plugin-sdk
+		0: {
+			PriorSchema: &s.Schema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state groupResourceModel
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state.Description = nullIfEmpty(state.Description)
+				state.ChannelID = nullIfEmpty(state.ChannelID)
+				if state.DeletionProtection.IsNull() {
+					state.DeletionProtection = types.BoolValue(r.client != nil && r.client.DeletionProtection)
+				}
+				if state.AdoptExisting.IsNull() {
+					state.AdoptExisting = types.BoolValue(r.client != nil && r.client.AdoptExisting)
+				}
+				if state.ActiveInstancesWindow.IsNull() {
+					state.ActiveInstancesWindow = types.StringValue("7d")
+				}
+				if state.AllowDeleteWithActiveInstances.IsNull() {
+					state.AllowDeleteWithActiveInstances = types.BoolValue(false)
+				}
+				if state.VerifyUpdateCheck == nil {
+					state.VerifyUpdateCheck = []verifyUpdateCheckModel{}
+				}
+				for i, check := range state.VerifyUpdateCheck {
+					state.VerifyUpdateCheck[i].ExpectedPackageID = nullIfEmpty(check.ExpectedPackageID)
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
 	}
+}
 
-	return resourceGroupRead(ctx, d, meta)
+// updateGroupInputFromGroup returns the input that updates a group to its
+// current settings
+func updateGroupInputFromGroup(g nebraska.Group) *nebraska.UpdateGroupInput {
+	return &nebraska.UpdateGroupInput{
+		Name:                      g.Name,
+		Description:               g.Description,
+		ChannelID:                 g.ChannelID,
+		PolicyUpdatesEnabled:      g.PolicyUpdatesEnabled,
+		PolicySafeMode:            g.PolicySafeMode,
+		PolicyOfficeHours:         g.PolicyOfficeHours,
+		PolicyTimezone:            g.PolicyTimezone,
+		PolicyPeriodInterval:      g.PolicyPeriodInterval,
+		PolicyMaxUpdatesPerPeriod: g.PolicyMaxUpdatesPerPeriod,
+		PolicyUpdateTimeout:       g.PolicyUpdateTimeout,
+		Track:                     g.Track,
+	}
 }
 
-func resourceGroupDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+// verifyGroupUpdateCheck performs the update check configured in
+// verify_update_check and reports when the expected package isn't offered
+func verifyGroupUpdateCheck(c *apiClient, appID string, m groupResourceModel) diag.Diagnostics {
+	if len(m.VerifyUpdateCheck) == 0 {
+		return nil
+	}
+	v := m.VerifyUpdateCheck[0]
+	id := m.ID.ValueString()
+
+	var diags diag.Diagnostics
+	failure := func(format string, a ...interface{}) diag.Diagnostics {
+		if v.OnFailure.ValueString() == "fail" {
+			diags.AddError("Update check verification failed", fmt.Sprintf(format, a...))
+		} else {
+			diags.AddWarning("Update check verification failed", fmt.Sprintf(format, a...))
+		}
+		return diags
+	}
+
+	packageID := v.ExpectedPackageID.ValueString()
+	if packageID == "" {
+		channelID := m.ChannelID.ValueString()
+		if channelID == "" {
+			return failure("Group %s doesn't provide a channel and no expected_package_id is set.", id)
+		}
+		channel, err := c.GetChannel(appID, channelID)
+		if err != nil {
+			return failure("Couldn't retrieve channel %s: %s", channelID, err)
+		}
+		packageID = channel.PackageID
+		if packageID == "" {
+			return failure("Channel %s of group %s doesn't provide a package.", channelID, id)
+		}
+	}
+	pkg, err := c.GetPackage(appID, packageID)
+	if err != nil {
+		return failure("Couldn't retrieve package %s: %s", packageID, err)
+	}
 
-	appID, err := getApplicationID(d, c)
+	track := m.Track.ValueString()
+	fromVersion := v.FromVersion.ValueString()
+	check, err := c.UpdateCheck(&nebraska.UpdateCheckInput{
+		AppID:     appID,
+		Track:     track,
+		Version:   fromVersion,
+		Arch:      v.Arch.ValueString(),
+		MachineID: v.MachineID.ValueString(),
+	})
 	if err != nil {
-		return diag.FromErr(err)
+		return failure("Update check on track %s failed: %s", track, err)
+	}
+	if !check.UpdateAvailable {
+		return failure("An instance on track %s running version %s was not offered package %s (%s): app status %q, update check status %q.", track, fromVersion, packageID, pkg.Version, check.AppStatus, check.Status)
 	}
-	if err := c.DeleteGroup(appID, d.Id()); err != nil {
-		return diag.FromErr(err)
+	if check.Version != pkg.Version {
+		return failure("An instance on track %s running version %s was offered version %s instead of package %s (%s).", track, fromVersion, check.Version, packageID, pkg.Version)
 	}
 
 	return nil
@@ -1,29 +1,43 @@
 package provider
 
 import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"regexp"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest"
+	"gotest.tools/assert"
 )
 
 func TestAccGroupResource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "nebraska_group.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourceGroup,
+				Config: fmt.Sprintf(testAccResourceGroup, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
 					resource.TestCheckResourceAttrSet(dsn, "rollout_in_progress"),
 					resource.TestCheckResourceAttrSet(dsn, "channel_id"),
 					resource.TestCheckResourceAttrSet(dsn, "created_ts"),
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
-					resource.TestCheckResourceAttr(dsn, "name", "terraform-test"),
+					resource.TestCheckResourceAttr(dsn, "name", rName),
 					resource.TestCheckResourceAttr(dsn, "description", "Test description"),
 					resource.TestCheckResourceAttr(dsn, "policy_updates_enabled", "false"),
 					resource.TestCheckResourceAttr(dsn, "policy_safe_mode", "true"),
@@ -34,6 +48,11 @@ func TestAccGroupResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(dsn, "policy_update_timeout", "35 minutes"),
 				),
 			},
+			{
+				ResourceName:      dsn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -43,20 +62,20 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test" {
-  version = "0.0.0"
+  version = "0.0.0-%[1]s"
   arch    = "amd64"
   url     = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "terraform-test"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test.id
 }
 
 resource "nebraska_group" "test" {
-  name                           = "terraform-test"
-  track                          = "terraform-test"
+  name                           = "%[1]s"
+  track                          = "%[1]s"
   description                    = "Test description"
   channel_id                     = nebraska_channel.test.id
   policy_updates_enabled         = "false"
@@ -68,3 +87,393 @@ resource "nebraska_group" "test" {
   policy_update_timeout          = "35 minutes"
 }
 `
+
+func TestAccGroupResource_verifyUpdateCheck(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_group.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceGroupVerifyUpdateCheck, rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttrSet(dsn, "id"),
+					resource.TestCheckResourceAttr(dsn, "verify_update_check.#", "1"),
+					resource.TestCheckResourceAttr(dsn, "verify_update_check.0.from_version", "0.0.0"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceGroupVerifyUpdateCheck, rName, false),
+				ExpectError: regexp.MustCompile("error-updatesDisabled"),
+			},
+		},
+	})
+}
+
+const testAccResourceGroupVerifyUpdateCheck = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version = "0.0.1-%[1]s"
+  arch    = "amd64"
+  url     = "http://fake-address/"
+}
+
+resource "nebraska_channel" "test" {
+  name       = "%[1]s"
+  arch       = "amd64"
+  package_id = nebraska_package.test.id
+}
+
+resource "nebraska_group" "test" {
+  name                   = "%[1]s"
+  track                  = "%[1]s"
+  channel_id             = nebraska_channel.test.id
+  policy_updates_enabled = %[2]t
+
+  verify_update_check {
+    from_version = "0.0.0"
+    arch         = "amd64"
+    on_failure   = "fail"
+  }
+}
+`
+
+func TestAccGroupResource_deletionProtection(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_group.test"
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceGroupDeletionProtection, rName, true),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "deletion_protection", "true"),
+					resource.TestCheckResourceAttr(dsn, "active_instances_window", "7d"),
+					resource.TestCheckResourceAttr(dsn, "allow_delete_with_active_instances", "false"),
+				),
+			},
+			{
+				Config:      fmt.Sprintf(testAccResourceGroupDeletionProtection, rName, true),
+				Destroy:     true,
+				ExpectError: regexp.MustCompile("Group is protected from deletion"),
+			},
+			{
+				Config: fmt.Sprintf(testAccResourceGroupDeletionProtection, rName, false),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "deletion_protection", "false"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceGroupDeletionProtection = `
+provider "nebraska" {
+  deletion_protection = %[2]t
+}
+
+resource "nebraska_group" "test" {
+  name  = "%[1]s"
+  track = "%[1]s"
+}
+`
+
+func TestAccGroupResource_adoptExisting(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_group.test"
+	var groupID string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+			// Add a group that isn't managed by terraform
+			group, err := testAccClient().AddGroup(os.Getenv("NEBRASKA_APPLICATION_ID"), &nebraska.AddGroupInput{
+				Name:                      rName,
+				Description:               "Created outside of terraform",
+				Track:                     rName,
+				PolicyPeriodInterval:      "1 minutes",
+				PolicyMaxUpdatesPerPeriod: 1,
+				PolicyUpdateTimeout:       "60 minutes",
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			groupID = group.ID
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourceGroupAdoptExisting, rName),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "adopt_existing", "true"),
+					resource.TestCheckResourceAttrPtr(dsn, "id", &groupID),
+					resource.TestCheckResourceAttr(dsn, "description", "Adopted by terraform"),
+					resource.TestCheckResourceAttr(dsn, "track", rName),
+					resource.TestCheckResourceAttr(dsn, "policy_max_updates_per_period", "9999999"),
+				),
+			},
+		},
+	})
+}
+
+const testAccResourceGroupAdoptExisting = `
+provider "nebraska" {
+  adopt_existing = true
+}
+
+resource "nebraska_group" "test" {
+  name        = "%[1]s"
+  description = "Adopted by terraform"
+}
+`
+
+func TestGroupResource(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	ch, err := fake.Client().AddChannel(nebraska.FlatcarApplicationID, &nebraska.AddChannelInput{
+		Name: "stable",
+		Arch: nebraska.ArchAMD64,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	state, diags := testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name":                          tftypes.NewValue(tftypes.String, "workers"),
+		"track":                         tftypes.NewValue(tftypes.String, "workers"),
+		"channel_id":                    tftypes.NewValue(tftypes.String, ch.ID),
+		"policy_updates_enabled":        tftypes.NewValue(tftypes.Bool, true),
+		"policy_timezone":               tftypes.NewValue(tftypes.String, "Europe/London"),
+		"policy_period_interval":        tftypes.NewValue(tftypes.String, "1 hours"),
+		"policy_max_updates_per_period": tftypes.NewValue(tftypes.Number, 5),
+		"policy_update_timeout":         tftypes.NewValue(tftypes.String, "1 days"),
+	})
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	var id string
+	if err := state["id"].As(&id); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	g, err := fake.Client().GetGroup(nebraska.FlatcarApplicationID, id)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if g.Name != "workers" || g.ChannelID != ch.ID || g.PolicyMaxUpdatesPerPeriod != 5 {
+		t.Errorf("got group %+v", g)
+	}
+}
+
+func TestGroupResourceAdoptExistingFailure(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	if _, err := fake.Client().AddGroup(nebraska.FlatcarApplicationID, &nebraska.AddGroupInput{
+		Name:                 "workers",
+		PolicyTimezone:       "Europe/London",
+		PolicyPeriodInterval: "1 hours",
+		PolicyUpdateTimeout:  "1 days",
+	}); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	// Nebraska rejects the update of the adopted group, which shouldn't be
+	// saved half adopted
+	state, diags := testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name":           tftypes.NewValue(tftypes.String, "workers"),
+		"channel_id":     tftypes.NewValue(tftypes.String, "missing"),
+		"adopt_existing": tftypes.NewValue(tftypes.Bool, true),
+	})
+	if len(diags) != 1 || diags[0].Summary != "Couldn't update group" {
+		t.Fatalf("got diagnostics %+v", diags)
+	}
+	if state != nil {
+		t.Errorf("got state %v", state)
+	}
+}
+
+func TestGroupResourceMachineID(t *testing.T) {
+	server, _ := newFakeTestServer(t)
+	schema := server.schema.ResourceSchemas["nebraska_group"]
+	var block *tfprotov5.SchemaNestedBlock
+	for _, b := range schema.Block.BlockTypes {
+		if b.TypeName == "verify_update_check" {
+			block = b
+		}
+	}
+
+	for machineID, valid := range map[string]bool{
+		"{9f1b5a34-5d0e-4c1a-8f76-7e3c2b1d0a5e}": true,
+		"{00000000-0000-0000-0000-000000000000}": true,
+		"9f1b5a34-5d0e-4c1a-8f76-7e3c2b1d0a5e":   false,
+		"terraform":                              false,
+	} {
+		config := testDynamicValue(t, schema, map[string]tftypes.Value{
+			"name": tftypes.NewValue(tftypes.String, "workers"),
+			"verify_update_check": tftypes.NewValue(tftypes.List{ElementType: block.Block.ValueType()}, []tftypes.Value{
+				testConfigValue(block.Block, map[string]tftypes.Value{
+					"from_version": tftypes.NewValue(tftypes.String, "0.0.0"),
+					"machine_id":   tftypes.NewValue(tftypes.String, machineID),
+				}),
+			}),
+		})
+		resp, err := server.ValidateResourceTypeConfig(context.Background(), &tfprotov5.ValidateResourceTypeConfigRequest{
+			TypeName: "nebraska_group",
+			Config:   &config,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if valid != (len(resp.Diagnostics) == 0) {
+			t.Errorf("machine_id %s: got diagnostics %+v", machineID, resp.Diagnostics)
+		}
+	}
+}
+
+func TestGroupResourceRequiresFeature(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	defer fake.Close()
+	fake.SetVersion("2.7.0")
+	server := newTestProviderServer(t, fake.URL)
+
+	_, diags := testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name":                    tftypes.NewValue(tftypes.String, "workers"),
+		"active_instances_window": tftypes.NewValue(tftypes.String, "1d"),
+	})
+	if len(diags) != 1 || diags[0].Summary != "active_instances_window requires Nebraska >= 2.8.0" {
+		t.Fatalf("got diagnostics %+v", diags)
+	}
+
+	// The default window is left to the server
+	_, diags = testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "workers"),
+	})
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+}
+
+func TestGroupResourceRequiresFeatureUndiscovered(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	defer fake.Close()
+	u, err := url.Parse(fake.URL)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	unhealthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/health" {
+			http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+			return
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer unhealthy.Close()
+
+	server, diags := configureTestProviderServer(t, unhealthy.URL)
+	if len(diags) != 1 || diags[0].Severity != tfprotov5.DiagnosticSeverityWarning || diags[0].Summary != "Couldn't discover the capabilities of the Nebraska server" {
+		t.Fatalf("got diagnostics %+v", diags)
+	}
+
+	_, diags = testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name":                    tftypes.NewValue(tftypes.String, "workers"),
+		"active_instances_window": tftypes.NewValue(tftypes.String, "1d"),
+	})
+	if len(diags) != 1 || diags[0].Summary != "active_instances_window requires Nebraska >= 2.8.0" {
+		t.Fatalf("got diagnostics %+v", diags)
+	}
+
+	// Groups that don't need the capabilities can still be managed
+	_, diags = testApplyResource(t, server, "nebraska_group", map[string]tftypes.Value{
+		"name": tftypes.NewValue(tftypes.String, "workers"),
+	})
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+}
+
+func TestGroupResourceUpgradeStateV0(t *testing.T) {
+	attrs := testUpgradeResourceState(t, "nebraska_group", `{
+  "id": "3a6a0b6e-7f0e-4c4c-9d77-3c59a3f8b1d2",
+  "name": "Stable",
+  "track": "stable",
+  "application_id": "e96281a6-d1af-4bde-9a0a-97b76e56dc57",
+  "description": "",
+  "created_ts": "2021-01-02 03:04:05 +0000 UTC",
+  "rollout_in_progress": false,
+  "channel_id": "5b810680-e36a-4879-b98a-4f989e80b899",
+  "policy_updates_enabled": true,
+  "policy_safe_mode": false,
+  "policy_office_hours": false,
+  "policy_timezone": "Europe/London",
+  "policy_period_interval": "1 minutes",
+  "policy_max_updates_per_period": 9999999,
+  "policy_update_timeout": "60 minutes",
+  "deletion_protection": true,
+  "adopt_existing": false,
+  "verify_update_check": []
+}`)
+
+	want := map[string]tftypes.Value{
+		"description":                        tftypes.NewValue(tftypes.String, nil),
+		"channel_id":                         tftypes.NewValue(tftypes.String, "5b810680-e36a-4879-b98a-4f989e80b899"),
+		"policy_max_updates_per_period":      tftypes.NewValue(tftypes.Number, 9999999),
+		"deletion_protection":                tftypes.NewValue(tftypes.Bool, true),
+		"active_instances_window":            tftypes.NewValue(tftypes.String, "7d"),
+		"allow_delete_with_active_instances": tftypes.NewValue(tftypes.Bool, false),
+	}
+	for k, v := range want {
+		if !attrs[k].Equal(v) {
+			t.Errorf("%s: got %s, want %s", k, attrs[k], v)
+		}
+	}
+	var checks []tftypes.Value
+	if err := attrs["verify_update_check"].As(&checks); err != nil || len(checks) != 0 {
+		t.Errorf("verify_update_check: got %s, want an empty list", attrs["verify_update_check"])
+	}
+}
+
+func TestGroupResourceUpgradeFromSDKState(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	ids := testSDKStateObjects(t, fake)
+
+	changed := testPlanSDKState(t, server, "nebraska_group", "nebraska_group", ids, map[string]tftypes.Value{
+		"name":                   tftypes.NewValue(tftypes.String, "Stable"),
+		"track":                  tftypes.NewValue(tftypes.String, "stable"),
+		"description":            tftypes.NewValue(tftypes.String, "Stable machines"),
+		"channel_id":             tftypes.NewValue(tftypes.String, ids["30a3a6f5-0315-4204-a760-ffc0f2916485"]),
+		"policy_timezone":        tftypes.NewValue(tftypes.String, "Europe/London"),
+		"policy_period_interval": tftypes.NewValue(tftypes.String, "15 minutes"),
+		"policy_update_timeout":  tftypes.NewValue(tftypes.String, "60 minutes"),
+	})
+	assert.DeepEqual(t, changed, []string(nil))
+}
+
+func TestAccGroupResource_upgradeFromSDK(t *testing.T) {
+	rName := testAccRandomName(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: sdkProvider,
+				Config:            fmt.Sprintf(testAccResourceGroup, rName),
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories,
+				Config:                   fmt.Sprintf(testAccResourceGroup, rName),
+				PlanOnly:                 true,
+			},
+		},
+	})
+}
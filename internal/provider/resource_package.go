@@ -2,367 +2,577 @@ package provider
 
 import (
 	"context"
-
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
-	"github.com/kinvolk/nebraska/backend/pkg/api"
-	"github.com/kinvolk/nebraska/backend/pkg/codegen"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringdefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
 )
 
-func resourcePackage() *schema.Resource {
-	return &schema.Resource{
-		Description: "A versioned package of the application.",
-
-		CreateContext: resourcePackageCreate,
-		ReadContext:   resourcePackageRead,
-		UpdateContext: resourcePackageUpdate,
-		DeleteContext: resourcePackageDelete,
-
-		Schema: map[string]*schema.Schema{
-			"version": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.StringIsNotEmpty,
-				Description:  "Package version.",
+var (
+	_ resource.ResourceWithConfigure    = &packageResource{}
+	_ resource.ResourceWithIdentity     = &packageResource{}
+	_ resource.ResourceWithImportState  = &packageResource{}
+	_ resource.ResourceWithUpgradeState = &packageResource{}
+)
+
+// NewPackageResource returns the nebraska_package resource
+func NewPackageResource() resource.Resource {
+	return &packageResource{}
+}
+
+type packageResource struct {
+	client *apiClient
+}
+
+type packageResourceModel struct {
+	ID                types.String         `tfsdk:"id"`
+	Version           types.String         `tfsdk:"version"`
+	URL               types.String         `tfsdk:"url"`
+	Arch              types.String         `tfsdk:"arch"`
+	Type              types.String         `tfsdk:"type"`
+	Filename          types.String         `tfsdk:"filename"`
+	Description       types.String         `tfsdk:"description"`
+	Size              types.String         `tfsdk:"size"`
+	Hash              types.String         `tfsdk:"hash"`
+	ChannelsBlacklist types.List           `tfsdk:"channels_blacklist"`
+	FlatcarAction     []flatcarActionModel `tfsdk:"flatcar_action"`
+	ApplicationID     types.String         `tfsdk:"application_id"`
+	CreatedTs         types.String         `tfsdk:"created_ts"`
+	OnDelete          types.String         `tfsdk:"on_delete"`
+}
+
+type flatcarActionModel struct {
+	ID                    types.String `tfsdk:"id"`
+	Event                 types.String `tfsdk:"event"`
+	ChromeOSVersion       types.String `tfsdk:"chromeos_version"`
+	Sha256                types.String `tfsdk:"sha256"`
+	NeedsAdmin            types.Bool   `tfsdk:"needs_admin"`
+	IsDelta               types.Bool   `tfsdk:"is_delta"`
+	DisablePayloadBackoff types.Bool   `tfsdk:"disable_payload_backoff"`
+	MetadataSignatureRsa  types.String `tfsdk:"metadata_signature_rsa"`
+	MetadataSize          types.String `tfsdk:"metadata_size"`
+	Deadline              types.String `tfsdk:"deadline"`
+	CreatedTs             types.String `tfsdk:"created_ts"`
+}
+
+func newFlatcarActionModel(action nebraska.FlatcarAction) flatcarActionModel {
+	return flatcarActionModel{
+		ID:                    types.StringValue(action.ID),
+		Event:                 types.StringValue(action.Event),
+		ChromeOSVersion:       types.StringValue(action.ChromeOSVersion),
+		Sha256:                types.StringValue(action.Sha256),
+		NeedsAdmin:            types.BoolValue(action.NeedsAdmin),
+		IsDelta:               types.BoolValue(action.IsDelta),
+		DisablePayloadBackoff: types.BoolValue(action.DisablePayloadBackoff),
+		MetadataSignatureRsa:  types.StringValue(action.MetadataSignatureRsa),
+		MetadataSize:          types.StringValue(action.MetadataSize),
+		Deadline:              types.StringValue(action.Deadline),
+		CreatedTs:             types.StringValue(action.CreatedTs.String()),
+	}
+}
+
+func (r *packageResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_package"
+}
+
+func (r *packageResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		MarkdownDescription: "A versioned package of the application.",
+		// Version 1 stores unset optional attributes as null rather than ""
+		// and only stores flatcar_action when it's configured
+		Version: 1,
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "ID of the package.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"version": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "Package version.",
+				Validators: []validator.String{
+					stringvalidator.LengthAtLeast(1),
+				},
+			},
+			"url": schema.StringAttribute{
+				Required:            true,
+				MarkdownDescription: "URL where the package is available.",
+				Validators: []validator.String{
+					stringvalidator.RegexMatches(httpURL, "must be a URL with an http or https scheme"),
+				},
 			},
-			"url": {
-				Type:         schema.TypeString,
-				Required:     true,
-				ValidateFunc: validation.IsURLWithHTTPorHTTPS,
-				Description:  "URL where the package is available.",
+			"arch": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(nebraska.ArchAll.String()),
+				MarkdownDescription: fmt.Sprintf("Package arch. Defaults to `%s`.", nebraska.ArchAll.String()),
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidArchs...),
+				},
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"arch": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ForceNew:     true,
-				ValidateFunc: validation.StringInSlice(nebraska.ValidArchs, false),
-				Default:      api.ArchAll.String(),
-				Description:  "Package arch.",
+			"type": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString(nebraska.PackageTypeFlatcar.String()),
+				MarkdownDescription: fmt.Sprintf("Type of package. Defaults to `%s`.", nebraska.PackageTypeFlatcar.String()),
+				Validators: []validator.String{
+					stringvalidator.OneOf(nebraska.ValidPackageTypes...),
+				},
 			},
-			"type": {
-				Type:         schema.TypeString,
-				Optional:     true,
-				ValidateFunc: validation.StringInSlice(nebraska.ValidPackageTypes, false),
-				Default:      nebraska.PackageTypeFlatcar.String(),
-				Description:  "Type of package.",
+			"filename": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The filename of the package.",
 			},
-			"filename": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The filename of the package.",
+			"description": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A description of the package.",
 			},
-			"description": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "A description of the package.",
+			"size": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "The size, in bytes.",
 			},
-			"size": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "The size, in bytes.",
+			"hash": schema.StringAttribute{
+				Optional:            true,
+				MarkdownDescription: "A base64 encoded sha1 hash of the package digest. Tip: `cat update.gz | openssl dgst -sha1 -binary | base64`.",
 			},
-			"hash": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				Description: "A base64 encoded sha1 hash of the package digest. Tip: `cat update.gz | openssl dgst -sha1 -binary | base64`.",
+			"channels_blacklist": schema.ListAttribute{
+				Optional:            true,
+				ElementType:         types.StringType,
+				MarkdownDescription: "A list of channels (by id) that cannot point to this package.",
 			},
-			"channels_blacklist": {
-				Type:     schema.TypeList,
-				Optional: true,
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+			"application_id": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				MarkdownDescription: "ID of the application this package belongs to.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+					stringplanmodifier.RequiresReplace(),
 				},
-				Description: "A list of channels (by id) that cannot point to this package.",
 			},
-			"flatcar_action": {
-				Type:        schema.TypeList,
-				MaxItems:    1,
-				Optional:    true,
-				Computed:    true,
-				Description: "A Flatcar specific Omaha action.",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"id": {
-							Type:     schema.TypeString,
+			"created_ts": schema.StringAttribute{
+				Computed:            true,
+				MarkdownDescription: "Creation timestamp.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"on_delete": schema.StringAttribute{
+				Optional:            true,
+				Computed:            true,
+				Default:             stringdefault.StaticString("fail"),
+				MarkdownDescription: "What to do when the package is deleted: `fail` if any channels still point at it, `detach` it from those channels first or `retain` it in Nebraska and only remove it from the state. Defaults to `fail`.",
+				Validators: []validator.String{
+					stringvalidator.OneOf("detach", "fail", "retain"),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"flatcar_action": schema.ListNestedBlock{
+				MarkdownDescription: "A Flatcar specific Omaha action. When it's omitted, the package keeps the action it has in Nebraska.",
+				Validators: []validator.List{
+					listvalidator.SizeAtMost(1),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"id": schema.StringAttribute{
 							Computed: true,
 						},
-						"event": {
-							Type:     schema.TypeString,
+						"event": schema.StringAttribute{
 							Computed: true,
 						},
-						"chromeos_version": {
-							Type:     schema.TypeString,
+						"chromeos_version": schema.StringAttribute{
 							Computed: true,
 						},
-						"sha256": {
-							Type:        schema.TypeString,
-							Required:    true,
-							Description: "A base64 encoded sha256 hash of the action. Tip: `cat update.gz | openssl dgst -sha256 -binary | base64`.",
+						"sha256": schema.StringAttribute{
+							Required:            true,
+							MarkdownDescription: "A base64 encoded sha256 hash of the action. Tip: `cat update.gz | openssl dgst -sha256 -binary | base64`.",
 						},
-						"needs_admin": {
-							Type:     schema.TypeBool,
+						"needs_admin": schema.BoolAttribute{
 							Computed: true,
 						},
-						"is_delta": {
-							Type:     schema.TypeBool,
+						"is_delta": schema.BoolAttribute{
 							Computed: true,
 						},
-						"disable_payload_backoff": {
-							Type:     schema.TypeBool,
+						"disable_payload_backoff": schema.BoolAttribute{
 							Computed: true,
 						},
-						"metadata_signature_rsa": {
-							Type:     schema.TypeString,
+						"metadata_signature_rsa": schema.StringAttribute{
 							Computed: true,
 						},
-						"metadata_size": {
-							Type:     schema.TypeString,
+						"metadata_size": schema.StringAttribute{
 							Computed: true,
 						},
-						"deadline": {
-							Type:     schema.TypeString,
+						"deadline": schema.StringAttribute{
 							Computed: true,
 						},
-						"created_ts": {
-							Type:     schema.TypeString,
+						"created_ts": schema.StringAttribute{
 							Computed: true,
 						},
 					},
 				},
 			},
-			"application_id": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Computed:    true,
-				Description: "ID of the application this package belongs to.",
-			},
-			"created_ts": {
-				Type:        schema.TypeString,
-				Computed:    true,
-				Description: "Creation timestamp.",
-			},
 		},
 	}
 }
 
-func resourcePackageCreate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *packageResource) IdentitySchema(ctx context.Context, req resource.IdentitySchemaRequest, resp *resource.IdentitySchemaResponse) {
+	resp.IdentitySchema = resourceIdentitySchema("package")
+}
+
+func (r *packageResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.client = configuredClient(req.ProviderData, &resp.Diagnostics)
+}
 
-	appID, err := getApplicationID(d, c)
+func (r *packageResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan packageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	appID, err := applicationID(plan.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create package", err.Error())
+		return
 	}
-	d.Set("application_id", appID)
+	plan.ApplicationID = types.StringValue(appID)
 
-	arch, err := api.ArchFromString(d.Get("arch").(string))
+	arch, err := nebraska.ArchFromString(plan.Arch.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create package", err.Error())
+		return
 	}
 
-	pkgType, err := nebraska.PackageTypeFromString(d.Get("type").(string))
+	pkgType, err := nebraska.PackageTypeFromString(plan.Type.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create package", err.Error())
+		return
+	}
+
+	var blacklist []string
+	resp.Diagnostics.Append(plan.ChannelsBlacklist.ElementsAs(ctx, &blacklist, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Nebraska rejects a null channels_blacklist, which is what a null list
+	// is decoded into
+	if blacklist == nil {
+		blacklist = []string{}
 	}
 
 	input := &nebraska.AddPackageInput{
 		Type:              pkgType,
-		Version:           d.Get("version").(string),
-		URL:               d.Get("url").(string),
-		Filename:          d.Get("filename").(string),
-		Description:       d.Get("description").(string),
-		Size:              d.Get("size").(string),
-		Hash:              d.Get("hash").(string),
-		ChannelsBlacklist: expandChannelBlacklist(d.Get("channels_blacklist").([]interface{})),
-		Arch:              codegen.Arch(arch),
+		Version:           plan.Version.ValueString(),
+		URL:               plan.URL.ValueString(),
+		Filename:          plan.Filename.ValueString(),
+		Description:       plan.Description.ValueString(),
+		Size:              plan.Size.ValueString(),
+		Hash:              plan.Hash.ValueString(),
+		ChannelsBlacklist: blacklist,
+		Arch:              arch,
 		ApplicationID:     nebraska.FlatcarApplicationID,
 		FlatcarAction: &nebraska.FlatcarActionInput{
-			Sha256: expandFlatcarActionSha256(d.Get("flatcar_action").([]interface{})),
+			Sha256: flatcarActionSha256(plan.FlatcarAction),
 		},
 	}
 
-	if len(input.ChannelsBlacklist) == 0 {
-		input.ChannelsBlacklist = make([]string, 0)
-	}
-	pkg, err := c.AddPackage(appID, input)
+	pkg, err := r.client.WithContext(ctx).AddPackage(appID, input)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't create package", err.Error())
+		return
 	}
+	plan.ID = types.StringValue(pkg.ID)
 
-	d.SetId(pkg.Id)
-
-	return resourcePackageRead(ctx, d, meta)
+	r.readInto(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
 }
 
-func resourcePackageRead(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
-
-	appID, err := getApplicationID(d, c)
-	if err != nil {
-		return diag.FromErr(err)
+func (r *packageResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state packageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
-	d.Set("application_id", appID)
 
-	pkg, err := c.GetPackage(appID, d.Id())
-	if err != nil {
-		if err == nebraska.ErrNotFound {
-			d.SetId("")
-			return nil
+	if !r.readInto(ctx, &state, &resp.Diagnostics) {
+		if !resp.Diagnostics.HasError() {
+			resp.State.RemoveResource(ctx)
 		}
-		return diag.FromErr(err)
-	}
-	if pkg == nil {
-		d.SetId("")
-		return nil
-	}
-
-	if err := d.Set("type", nebraska.PackageType(pkg.Type).String()); err != nil {
-		return diag.FromErr(err)
+		return
 	}
 
-	if err := d.Set("version", pkg.Version); err != nil {
-		return diag.FromErr(err)
-	}
-
-	if err := d.Set("url", pkg.Url); err != nil {
-		return diag.FromErr(err)
-	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, state.ApplicationID, state.ID)...)
+}
 
-	if err := d.Set("filename", pkg.Filename); err != nil {
-		return diag.FromErr(err)
+// readInto refreshes the model from Nebraska. It returns false when the
+// package doesn't exist or can't be read.
+func (r *packageResource) readInto(ctx context.Context, m *packageResourceModel, diags *diag.Diagnostics) bool {
+	appID, err := applicationID(m.ApplicationID, r.client)
+	if err != nil {
+		diags.AddError("Couldn't read package", err.Error())
+		return false
 	}
+	m.ApplicationID = types.StringValue(appID)
 
-	if err := d.Set("description", pkg.Description); err != nil {
-		return diag.FromErr(err)
+	pkg, err := r.client.WithContext(ctx).GetPackage(appID, m.ID.ValueString())
+	if err != nil {
+		if err == nebraska.ErrNotFound {
+			return false
+		}
+		diags.AddError("Couldn't read package", err.Error())
+		return false
 	}
 
-	if err := d.Set("size", pkg.Size); err != nil {
-		return diag.FromErr(err)
-	}
+	diags.Append(m.setPackage(ctx, pkg)...)
 
-	if err := d.Set("created_ts", pkg.CreatedTs.String()); err != nil {
-		return diag.FromErr(err)
-	}
+	return true
+}
 
-	if err := d.Set("channels_blacklist", pkg.ChannelsBlacklist); err != nil {
-		return diag.FromErr(err)
-	}
+// setPackage sets the attributes of the model that are read from Nebraska
+func (m *packageResourceModel) setPackage(ctx context.Context, pkg *nebraska.Package) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	m.Type = types.StringValue(pkg.Type.String())
+	m.Version = types.StringValue(pkg.Version)
+	m.URL = types.StringValue(pkg.URL)
+	m.Filename = optionalString(m.Filename, pkg.Filename)
+	m.Description = optionalString(m.Description, pkg.Description)
+	m.Size = optionalString(m.Size, pkg.Size)
+	m.Hash = optionalString(m.Hash, pkg.Hash)
+	m.CreatedTs = types.StringValue(pkg.CreatedTs.String())
+	m.Arch = types.StringValue(pkg.Arch.String())
+
+	if len(pkg.ChannelsBlacklist) == 0 && m.ChannelsBlacklist.IsNull() {
+		m.ChannelsBlacklist = types.ListNull(types.StringType)
+	} else {
+		blacklist, d := types.ListValueFrom(ctx, types.StringType, pkg.ChannelsBlacklist)
+		diags.Append(d...)
+		m.ChannelsBlacklist = blacklist
+	}
+
+	// Blocks can't be computed, so the action is only stored when it's
+	// configured, and otherwise left to Nebraska
+	hadAction := len(m.FlatcarAction) > 0
+	m.FlatcarAction = []flatcarActionModel{}
+	if pkg.FlatcarAction != nil && hadAction {
+		m.FlatcarAction = append(m.FlatcarAction, newFlatcarActionModel(*pkg.FlatcarAction))
+	}
+
+	return diags
+}
 
-	if err := d.Set("flatcar_action", flattenFlatcarAction(pkg.FlatcarAction)); err != nil {
-		return diag.FromErr(err)
+func (r *packageResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan packageResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
 	}
 
-	if err := d.Set("arch", api.Arch(pkg.Arch).String()); err != nil {
-		return diag.FromErr(err)
+	appID, err := applicationID(plan.ApplicationID, r.client)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't update package", err.Error())
+		return
 	}
+	plan.ApplicationID = types.StringValue(appID)
 
-	return nil
-}
-
-func resourcePackageUpdate(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
-
-	appID, err := getApplicationID(d, c)
+	arch, err := nebraska.ArchFromString(plan.Arch.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't update package", err.Error())
+		return
 	}
-	d.Set("application_id", appID)
 
-	arch, err := api.ArchFromString(d.Get("arch").(string))
+	pkgType, err := nebraska.PackageTypeFromString(plan.Type.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't update package", err.Error())
+		return
 	}
 
-	pkgType, err := nebraska.PackageTypeFromString(d.Get("type").(string))
-	if err != nil {
-		return diag.FromErr(err)
+	var blacklist []string
+	resp.Diagnostics.Append(plan.ChannelsBlacklist.ElementsAs(ctx, &blacklist, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	// Nebraska rejects a null channels_blacklist, which is what a null list
+	// is decoded into
+	if blacklist == nil {
+		blacklist = []string{}
+	}
+
+	// Keep the action in Nebraska when it isn't configured
+	sha256 := flatcarActionSha256(plan.FlatcarAction)
+	if len(plan.FlatcarAction) == 0 {
+		pkg, err := r.client.WithContext(ctx).Client.GetPackage(appID, plan.ID.ValueString())
+		if err != nil {
+			resp.Diagnostics.AddError("Couldn't update package", err.Error())
+			return
+		}
+		if pkg.FlatcarAction != nil {
+			sha256 = pkg.FlatcarAction.Sha256
+		}
 	}
 
 	input := &nebraska.UpdatePackageInput{
 		Type:              pkgType,
-		Version:           d.Get("version").(string),
-		URL:               d.Get("url").(string),
-		Filename:          d.Get("filename").(string),
-		Description:       d.Get("description").(string),
-		Size:              d.Get("size").(string),
-		Hash:              d.Get("hash").(string),
-		ChannelsBlacklist: expandChannelBlacklist(d.Get("channels_blacklist").([]interface{})),
-		Arch:              codegen.Arch(arch),
+		Version:           plan.Version.ValueString(),
+		URL:               plan.URL.ValueString(),
+		Filename:          plan.Filename.ValueString(),
+		Description:       plan.Description.ValueString(),
+		Size:              plan.Size.ValueString(),
+		Hash:              plan.Hash.ValueString(),
+		ChannelsBlacklist: blacklist,
+		Arch:              arch,
 		ApplicationID:     nebraska.FlatcarApplicationID,
 		FlatcarAction: &nebraska.FlatcarActionInput{
-			Sha256: expandFlatcarActionSha256(d.Get("flatcar_action").([]interface{})),
+			Sha256: sha256,
 		},
 	}
 
-	if len(input.ChannelsBlacklist) == 0 {
-		input.ChannelsBlacklist = make([]string, 0)
-	}
-	if _, err := c.UpdatePackage(appID, d.Id(), input); err != nil {
-		return diag.FromErr(err)
+	if _, err := r.client.WithContext(ctx).UpdatePackage(appID, plan.ID.ValueString(), input); err != nil {
+		resp.Diagnostics.AddError("Couldn't update package", err.Error())
+		return
 	}
 
-	return resourcePackageRead(ctx, d, meta)
+	r.readInto(ctx, &plan, &resp.Diagnostics)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+	resp.Diagnostics.Append(setIdentity(ctx, resp.Identity, plan.ApplicationID, plan.ID)...)
 }
 
-func resourcePackageDelete(ctx context.Context, d *schema.ResourceData, meta interface{}) diag.Diagnostics {
-	c := meta.(*apiClient)
+func (r *packageResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state packageResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	appID, err := getApplicationID(d, c)
+	appID, err := applicationID(state.ApplicationID, r.client)
 	if err != nil {
-		return diag.FromErr(err)
-	}
-	if err := c.DeletePackage(appID, d.Id()); err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Couldn't delete package", err.Error())
+		return
 	}
+	id := state.ID.ValueString()
 
-	return nil
-}
+	onDelete := state.OnDelete.ValueString()
+	if onDelete == "retain" {
+		return
+	}
 
-func expandChannelBlacklist(l []interface{}) []string {
-	var blacklist []string
-	for _, i := range l {
-		if v, ok := i.(string); ok {
-			blacklist = append(blacklist, v)
+	channels, err := channelsWithPackage(r.client.WithContext(ctx).Client, appID, id)
+	if err != nil {
+		resp.Diagnostics.AddError("Couldn't delete package", err.Error())
+		return
+	}
+	if len(channels) > 0 {
+		if onDelete != "detach" {
+			names := make([]string, 0, len(channels))
+			for _, ch := range channels {
+				names = append(names, fmt.Sprintf("%q (%s, %s)", ch.Name, ch.Arch.String(), ch.ID))
+			}
+			resp.Diagnostics.AddError("Package is still in use", fmt.Sprintf("Package %s is provided by the channels: %s. Point them at another package first, set on_delete to \"detach\" to remove the package from them or to \"retain\" to keep the package in Nebraska.", id, strings.Join(names, ", ")))
+			return
+		}
+		for _, ch := range channels {
+			input := updateChannelInputFromChannel(appID, ch)
+			input.PackageID = ""
+			if _, err := r.client.WithContext(ctx).UpdateChannel(appID, ch.ID, input); err != nil {
+				resp.Diagnostics.AddError("Couldn't delete package", fmt.Sprintf("couldn't detach package %s from channel %s: %s", id, ch.ID, err))
+				return
+			}
 		}
 	}
 
-	return blacklist
+	if err := r.client.WithContext(ctx).DeletePackage(appID, id); err != nil {
+		resp.Diagnostics.AddError("Couldn't delete package", err.Error())
+	}
 }
 
-func expandFlatcarActionSha256(l []interface{}) string {
-	if len(l) == 0 || l[0] == nil {
-		return ""
+func (r *packageResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	importApplicationID(ctx, req, resp)
+	resp.Diagnostics.Append(resp.State.SetAttribute(ctx, path.Root("on_delete"), "fail")...)
+}
+
+func (r *packageResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var s resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &s)
+
+	return map[int64]resource.StateUpgrader{
+		// State written by terraform-plugin-sdk
+		0: {
+			PriorSchema: &s.Schema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state packageResourceModel
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+
+				state.Filename = nullIfEmpty(state.Filename)
+				state.Description = nullIfEmpty(state.Description)
+				state.Size = nullIfEmpty(state.Size)
+				state.Hash = nullIfEmpty(state.Hash)
+				if len(state.ChannelsBlacklist.Elements()) == 0 {
+					state.ChannelsBlacklist = types.ListNull(types.StringType)
+				}
+				actions := []flatcarActionModel{}
+				for _, action := range state.FlatcarAction {
+					if action.Sha256.ValueString() != "" {
+						actions = append(actions, action)
+					}
+				}
+				state.FlatcarAction = actions
+				if state.OnDelete.IsNull() {
+					state.OnDelete = types.StringValue("fail")
+				}
+
+				resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+			},
+		},
 	}
+}
 
-	m := l[0].(map[string]interface{})
+// channelsWithPackage returns the channels in the application that point at
+// the given package. It takes the client without the read cache, so that
+// deletes are decided on what the server has now.
+func channelsWithPackage(c *nebraska.Client, appID, packageID string) ([]nebraska.Channel, error) {
+	channelPage, err := c.ListChannels(appID)
+	if err != nil {
+		return nil, err
+	}
+	if channelPage.Count != channelPage.TotalCount {
+		return nil, fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
+	}
 
-	if v, ok := m["sha256"].(string); ok {
-		return v
+	var channels []nebraska.Channel
+	for _, ch := range channelPage.Channels {
+		if ch.PackageID == packageID {
+			channels = append(channels, ch)
+		}
 	}
 
-	return ""
+	return channels, nil
 }
 
-func flattenFlatcarAction(action *codegen.FlatcarAction) []map[string]interface{} {
-	if action == nil {
-		return []map[string]interface{}{}
-	}
-
-	return []map[string]interface{}{
-		{
-			"id":                      action.Id,
-			"event":                   action.Event,
-			"chromeos_version":        action.ChromeOSVersion,
-			"sha256":                  action.Sha256,
-			"needs_admin":             action.NeedsAdmin,
-			"is_delta":                action.IsDelta,
-			"disable_payload_backoff": action.DisablePayloadBackoff,
-			"metadata_signature_rsa":  action.MetadataSignatureRsa,
-			"metadata_size":           action.MetadataSize,
-			"deadline":                action.Deadline,
-			"created_ts":              action.CreatedTs.String(),
-		},
+func flatcarActionSha256(l []flatcarActionModel) string {
+	if len(l) == 0 {
+		return ""
 	}
+
+	return l[0].Sha256.ValueString()
 }
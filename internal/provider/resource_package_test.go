@@ -1,27 +1,35 @@
 package provider
 
 import (
+	"fmt"
+	"net/http"
+	"os"
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
 	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/hashicorp/terraform-plugin-sdk/v2/terraform"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
 )
 
 func TestAccPackageResource_basic(t *testing.T) {
+	rName := testAccRandomName(t)
 	dsn := "nebraska_package.test"
 
 	resource.UnitTest(t, resource.TestCase{
 		PreCheck: func() {
 			testAccPreCheck(t)
 		},
-		ProviderFactories: providerFactories,
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
 		Steps: []resource.TestStep{
 			{
-				Config: testAccResourcePackage,
+				Config: fmt.Sprintf(testAccResourcePackage, rName),
 				Check: resource.ComposeTestCheckFunc(
 					resource.TestCheckResourceAttrSet(dsn, "id"),
 					resource.TestCheckResourceAttrSet(dsn, "created_ts"),
 					resource.TestCheckResourceAttrSet(dsn, "application_id"),
-					resource.TestCheckResourceAttr(dsn, "version", "0.0.0"),
+					resource.TestCheckResourceAttr(dsn, "version", "0.0.0-"+rName),
 					resource.TestCheckResourceAttr(dsn, "arch", "amd64"),
 					resource.TestCheckResourceAttr(dsn, "type", "flatcar"),
 					resource.TestCheckResourceAttr(dsn, "url", "http://fake-address/"),
@@ -31,6 +39,11 @@ func TestAccPackageResource_basic(t *testing.T) {
 					resource.TestCheckResourceAttr(dsn, "hash", "r3nufcxgMTZaxYEqL+x2zIoeClk="),
 				),
 			},
+			{
+				ResourceName:      dsn,
+				ImportState:       true,
+				ImportStateVerify: true,
+			},
 		},
 	})
 }
@@ -40,19 +53,19 @@ provider "nebraska" {
 }
 
 resource "nebraska_package" "test_0" {
-  version  = "0.1.0"
+  version  = "0.1.0-%[1]s"
   arch     = "amd64"
   url      = "http://fake-address/"
 }
 
 resource "nebraska_channel" "test" {
-  name       = "terraform-test"
+  name       = "%[1]s"
   arch       = "amd64"
   package_id = nebraska_package.test_0.id
 }
 
 resource "nebraska_package" "test" {
-  version  = "0.0.0"
+  version  = "0.0.0-%[1]s"
   arch     = "amd64"
   url      = "http://fake-address/"
   type     = "flatcar"
@@ -65,3 +78,302 @@ resource "nebraska_package" "test" {
   ]
 }
 `
+
+func TestAccPackageResource_onDeleteRetain(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_package.test"
+	var packageID string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			c := testAccClient()
+			appID := os.Getenv("NEBRASKA_APPLICATION_ID")
+			if _, err := c.GetPackage(appID, packageID); err != nil {
+				return fmt.Errorf("retained package %s: %w", packageID, err)
+			}
+
+			return c.DeletePackage(appID, packageID)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourcePackageOnDelete, "0.0.1-"+rName, "retain"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "on_delete", "retain"),
+					func(s *terraform.State) error {
+						packageID = s.RootModule().Resources[dsn].Primary.ID
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccPackageResource_onDeleteDetach(t *testing.T) {
+	rName := testAccRandomName(t)
+	dsn := "nebraska_package.test"
+	var channelID string
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		ProtoV5ProviderFactories: protoV5ProviderFactories,
+		CheckDestroy: func(s *terraform.State) error {
+			c := testAccClient()
+			appID := os.Getenv("NEBRASKA_APPLICATION_ID")
+			channel, err := c.GetChannel(appID, channelID)
+			if err != nil {
+				return err
+			}
+			if channel.PackageID != "" {
+				return fmt.Errorf("channel %s still points at package %s", channelID, channel.PackageID)
+			}
+
+			return c.DeleteChannel(appID, channelID)
+		},
+		Steps: []resource.TestStep{
+			{
+				Config: fmt.Sprintf(testAccResourcePackageOnDelete, "0.0.2-"+rName, "detach"),
+				Check: resource.ComposeTestCheckFunc(
+					resource.TestCheckResourceAttr(dsn, "on_delete", "detach"),
+					// Add a channel that isn't managed by terraform
+					func(s *terraform.State) error {
+						rs := s.RootModule().Resources[dsn]
+						channel, err := testAccClient().AddChannel(rs.Primary.Attributes["application_id"], &nebraska.AddChannelInput{
+							Name:      rName,
+							PackageID: rs.Primary.ID,
+							Arch:      nebraska.ArchAMD64,
+						})
+						if err != nil {
+							return err
+						}
+						channelID = channel.ID
+
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+const testAccResourcePackageOnDelete = `
+provider "nebraska" {
+}
+
+resource "nebraska_package" "test" {
+  version   = "%s"
+  arch      = "amd64"
+  url       = "http://fake-address/"
+  on_delete = "%s"
+}
+`
+
+func TestPackageResource(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+
+	state, diags := testApplyResource(t, server, "nebraska_package", map[string]tftypes.Value{
+		"version": tftypes.NewValue(tftypes.String, "3602.2.0"),
+		"arch":    tftypes.NewValue(tftypes.String, "amd64"),
+		"url":     tftypes.NewValue(tftypes.String, "https://update.release.flatcar-linux.net/amd64-usr/3602.2.0/"),
+	})
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	var id string
+	if err := state["id"].As(&id); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	pkg, err := fake.Client().GetPackage(nebraska.FlatcarApplicationID, id)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if pkg.Version != "3602.2.0" || pkg.Arch != nebraska.ArchAMD64 {
+		t.Errorf("got package %s (%s), want 3602.2.0 (amd64)", pkg.Version, pkg.Arch)
+	}
+}
+
+func TestPackageResourceEmptyBlacklist(t *testing.T) {
+	path := "/api/apps/" + nebraska.FlatcarApplicationID + "/packages"
+	pkg := nebraska.Package{
+		ID:                "p1",
+		ApplicationID:     nebraska.FlatcarApplicationID,
+		Arch:              nebraska.ArchAMD64,
+		Type:              nebraska.PackageTypeFlatcar,
+		Version:           "3602.2.0",
+		URL:               "https://update.release.flatcar-linux.net/amd64-usr/3602.2.0/",
+		ChannelsBlacklist: []string{},
+	}
+	server := newTestServer(t, map[string]any{
+		"POST " + path: pkg,
+		path + "/p1":   pkg,
+	})
+	config := map[string]tftypes.Value{
+		"version": tftypes.NewValue(tftypes.String, "3602.2.0"),
+		"arch":    tftypes.NewValue(tftypes.String, "amd64"),
+		"url":     tftypes.NewValue(tftypes.String, pkg.URL),
+	}
+
+	state, diags := testApplyResource(t, server, "nebraska_package", config)
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+	config["description"] = tftypes.NewValue(tftypes.String, "updated")
+	_, diags = testUpdateResource(t, server, "nebraska_package", state, config)
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+
+	// Nebraska rejects a null channels_blacklist
+	assert.Equal(t, len(server.writes), 2)
+	assert.Equal(t, server.writes[0].Method, http.MethodPost)
+	assert.DeepEqual(t, server.writes[0].Body["channels_blacklist"], []any{})
+	assert.Equal(t, server.writes[1].Method, http.MethodPut)
+	assert.DeepEqual(t, server.writes[1].Body["channels_blacklist"], []any{})
+}
+
+func TestPackageResourceUpgradeStateV0(t *testing.T) {
+	// terraform-plugin-sdk stored the flatcar_action that Nebraska returns
+	// for packages without one
+	attrs := testUpgradeResourceState(t, "nebraska_package", `{
+  "id": "2ba4c984-5e9b-411e-b7c3-b9a1a1c1e0d6",
+  "version": "2905.2.0",
+  "url": "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/",
+  "arch": "amd64",
+  "type": "flatcar",
+  "filename": "flatcar_production_update.gz",
+  "description": "",
+  "size": "",
+  "hash": "",
+  "channels_blacklist": [],
+  "flatcar_action": [
+    {
+      "id": "",
+      "event": "",
+      "chromeos_version": "",
+      "sha256": "",
+      "needs_admin": false,
+      "is_delta": false,
+      "disable_payload_backoff": false,
+      "metadata_signature_rsa": "",
+      "metadata_size": "",
+      "deadline": "",
+      "created_ts": "0001-01-01 00:00:00 +0000 UTC"
+    }
+  ],
+  "application_id": "e96281a6-d1af-4bde-9a0a-97b76e56dc57",
+  "created_ts": "2021-01-02 03:04:05 +0000 UTC"
+}`)
+
+	want := map[string]tftypes.Value{
+		"filename":           tftypes.NewValue(tftypes.String, "flatcar_production_update.gz"),
+		"description":        tftypes.NewValue(tftypes.String, nil),
+		"size":               tftypes.NewValue(tftypes.String, nil),
+		"hash":               tftypes.NewValue(tftypes.String, nil),
+		"channels_blacklist": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, nil),
+		"on_delete":          tftypes.NewValue(tftypes.String, "fail"),
+	}
+	for k, v := range want {
+		if !attrs[k].Equal(v) {
+			t.Errorf("%s: got %s, want %s", k, attrs[k], v)
+		}
+	}
+	var actions []tftypes.Value
+	if err := attrs["flatcar_action"].As(&actions); err != nil || len(actions) != 0 {
+		t.Errorf("flatcar_action: got %s, want an empty list", attrs["flatcar_action"])
+	}
+}
+
+func TestPackageResourceUpgradeFromSDKState(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	ids := testSDKStateObjects(t, fake)
+
+	changed := testPlanSDKState(t, server, "nebraska_package", "nebraska_package", ids, map[string]tftypes.Value{
+		"version":  tftypes.NewValue(tftypes.String, "2905.2.0"),
+		"arch":     tftypes.NewValue(tftypes.String, "amd64"),
+		"url":      tftypes.NewValue(tftypes.String, "https://update.release.flatcar-linux.net/amd64-usr/2905.2.0/"),
+		"filename": tftypes.NewValue(tftypes.String, "flatcar_production_update.gz"),
+	})
+	assert.DeepEqual(t, changed, []string(nil))
+
+	block := server.schema.ResourceSchemas["nebraska_package"].Block.BlockTypes[0]
+	assert.Equal(t, block.TypeName, "flatcar_action")
+	changed = testPlanSDKState(t, server, "nebraska_package", "nebraska_package_flatcar_action", ids, map[string]tftypes.Value{
+		"version":     tftypes.NewValue(tftypes.String, "2905.2.1"),
+		"arch":        tftypes.NewValue(tftypes.String, "amd64"),
+		"url":         tftypes.NewValue(tftypes.String, "https://update.release.flatcar-linux.net/amd64-usr/2905.2.1/"),
+		"filename":    tftypes.NewValue(tftypes.String, "flatcar_production_update.gz"),
+		"description": tftypes.NewValue(tftypes.String, "Flatcar 2905.2.1"),
+		"size":        tftypes.NewValue(tftypes.String, "465881871"),
+		"hash":        tftypes.NewValue(tftypes.String, "r3nufcxgMTZaxYEqL+x2zIoeClk="),
+		"flatcar_action": tftypes.NewValue(tftypes.List{ElementType: block.Block.ValueType()}, []tftypes.Value{
+			testConfigValue(block.Block, map[string]tftypes.Value{
+				"sha256": tftypes.NewValue(tftypes.String, "cSBzKN9c20GxWnZs7zFUqSgJjmU7Iqh1Eu2/mS5J5Jc="),
+			}),
+		}),
+	})
+	assert.DeepEqual(t, changed, []string(nil))
+}
+
+func TestPackageResourceFlatcarActionNotConfigured(t *testing.T) {
+	server, fake := newFakeTestServer(t)
+	ids := testSDKStateObjects(t, fake)
+	id := ids["2e29f185-e9aa-404b-8dfc-dab7cc0c2c98"]
+	config := map[string]tftypes.Value{
+		"version":     tftypes.NewValue(tftypes.String, "2905.2.1"),
+		"arch":        tftypes.NewValue(tftypes.String, "amd64"),
+		"url":         tftypes.NewValue(tftypes.String, "https://update.release.flatcar-linux.net/amd64-usr/2905.2.1/"),
+		"filename":    tftypes.NewValue(tftypes.String, "flatcar_production_update.gz"),
+		"description": tftypes.NewValue(tftypes.String, "Flatcar 2905.2.1"),
+		"size":        tftypes.NewValue(tftypes.String, "465881871"),
+		"hash":        tftypes.NewValue(tftypes.String, "r3nufcxgMTZaxYEqL+x2zIoeClk="),
+	}
+	schema := server.schema.ResourceSchemas["nebraska_package"]
+
+	// An imported package has nothing to change
+	imported := testImportResource(t, server, "nebraska_package", id)
+	assert.DeepEqual(t, testPlanResource(t, server, "nebraska_package", imported, config), []string(nil))
+
+	// terraform-plugin-sdk stored the action, which is dropped from the state
+	// but kept in Nebraska
+	upgraded := testSDKState(t, server, "nebraska_package", "nebraska_package_flatcar_action", ids)
+	assert.DeepEqual(t, testPlanResource(t, server, "nebraska_package", upgraded, config), []string{"flatcar_action"})
+	state, diags := testUpdateResource(t, server, "nebraska_package", testAttributes(t, schema.ValueType(), upgraded), config)
+	for _, d := range diags {
+		t.Fatalf("%s: %s", d.Summary, d.Detail)
+	}
+	var actions []tftypes.Value
+	if err := state["flatcar_action"].As(&actions); err != nil || len(actions) != 0 {
+		t.Errorf("flatcar_action: got %s, want an empty list", state["flatcar_action"])
+	}
+	pkg, err := fake.Client().GetPackage(nebraska.FlatcarApplicationID, id)
+	assert.NilError(t, err)
+	assert.Equal(t, pkg.FlatcarAction.Sha256, "cSBzKN9c20GxWnZs7zFUqSgJjmU7Iqh1Eu2/mS5J5Jc=")
+}
+
+func TestAccPackageResource_upgradeFromSDK(t *testing.T) {
+	rName := testAccRandomName(t)
+
+	resource.UnitTest(t, resource.TestCase{
+		PreCheck: func() {
+			testAccPreCheck(t)
+		},
+		Steps: []resource.TestStep{
+			{
+				ExternalProviders: sdkProvider,
+				Config:            fmt.Sprintf(testAccResourcePackage, rName),
+			},
+			{
+				ProtoV5ProviderFactories: protoV5ProviderFactories,
+				Config:                   fmt.Sprintf(testAccResourcePackage, rName),
+				PlanOnly:                 true,
+			},
+		},
+	})
+}
@@ -0,0 +1,192 @@
+package provider
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/resource"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest"
+)
+
+// The sweepers delete the objects left behind by failed acceptance test runs,
+// which have testAccPrefix in their names. Groups are swept before the
+// channels they provide, and channels before the packages they point at.
+//
+//	go test ./internal/provider -v -sweep=all
+func init() {
+	resource.AddTestSweepers("nebraska_group", &resource.Sweeper{
+		Name: "nebraska_group",
+		F:    testSweepGroups,
+	})
+	resource.AddTestSweepers("nebraska_channel", &resource.Sweeper{
+		Name:         "nebraska_channel",
+		Dependencies: []string{"nebraska_group"},
+		F:            testSweepChannels,
+	})
+	resource.AddTestSweepers("nebraska_package", &resource.Sweeper{
+		Name:         "nebraska_package",
+		Dependencies: []string{"nebraska_channel"},
+		F:            testSweepPackages,
+	})
+}
+
+// testSweepClient returns a client for the Nebraska server that the sweepers
+// clean up, and the application they sweep
+func testSweepClient() (*nebraska.Client, string, error) {
+	if os.Getenv("NEBRASKA_ENDPOINT") == "" {
+		return nil, "", errors.New("NEBRASKA_ENDPOINT must be set for sweepers")
+	}
+	appID := os.Getenv("NEBRASKA_APPLICATION_ID")
+	if appID == "" {
+		appID = nebraska.FlatcarApplicationID
+	}
+
+	return testAccClient(), appID, nil
+}
+
+func testSweepGroups(_ string) error {
+	c, appID, err := testSweepClient()
+	if err != nil {
+		return err
+	}
+	groups, err := c.ListGroups(appID)
+	if err != nil {
+		return fmt.Errorf("couldn't list groups: %w", err)
+	}
+
+	var errs []error
+	for _, g := range groups.Groups {
+		if !strings.HasPrefix(g.Name, testAccPrefix) {
+			continue
+		}
+		if err := c.DeleteGroup(appID, g.ID); err != nil && err != nebraska.ErrNotFound {
+			errs = append(errs, fmt.Errorf("couldn't delete group %s (%s): %w", g.Name, g.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func testSweepChannels(_ string) error {
+	c, appID, err := testSweepClient()
+	if err != nil {
+		return err
+	}
+	channels, err := c.ListChannels(appID)
+	if err != nil {
+		return fmt.Errorf("couldn't list channels: %w", err)
+	}
+
+	var errs []error
+	for _, ch := range channels.Channels {
+		if !strings.HasPrefix(ch.Name, testAccPrefix) {
+			continue
+		}
+		if err := c.DeleteChannel(appID, ch.ID); err != nil && err != nebraska.ErrNotFound {
+			errs = append(errs, fmt.Errorf("couldn't delete channel %s (%s): %w", ch.Name, ch.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func testSweepPackages(_ string) error {
+	c, appID, err := testSweepClient()
+	if err != nil {
+		return err
+	}
+	packages, err := c.SearchPackages(appID, testAccPrefix)
+	if err != nil {
+		return fmt.Errorf("couldn't list packages: %w", err)
+	}
+
+	var errs []error
+	for _, p := range packages.Packages {
+		if !strings.Contains(p.Version, "-"+testAccPrefix) {
+			continue
+		}
+		if err := c.DeletePackage(appID, p.ID); err != nil && err != nebraska.ErrNotFound {
+			errs = append(errs, fmt.Errorf("couldn't delete package %s (%s): %w", p.Version, p.ID, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func TestSweepers(t *testing.T) {
+	fake := nebraskatest.NewServer()
+	t.Cleanup(fake.Close)
+	t.Setenv("NEBRASKA_ENDPOINT", fake.URL)
+	t.Setenv("NEBRASKA_APPLICATION_ID", "")
+	c := fake.Client()
+	appID := nebraska.FlatcarApplicationID
+
+	// An object left behind by a test, and one that isn't a test's, of each
+	// type, with the test's objects depending on each other
+	var kept []string
+	for _, name := range []string{testAccPrefix + "-1", "stable"} {
+		version := "0.0.1"
+		if name != "stable" {
+			version += "-" + name
+		}
+		pkg, err := c.AddPackage(appID, &nebraska.AddPackageInput{
+			Arch:              nebraska.ArchAMD64,
+			ChannelsBlacklist: []string{},
+			Type:              nebraska.PackageTypeFlatcar,
+			Version:           version,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		ch, err := c.AddChannel(appID, &nebraska.AddChannelInput{
+			Name:      name,
+			Arch:      nebraska.ArchAMD64,
+			PackageID: pkg.ID,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		g, err := c.AddGroup(appID, &nebraska.AddGroupInput{
+			Name:      name,
+			ChannelID: ch.ID,
+		})
+		if err != nil {
+			t.Fatalf("err: %s", err)
+		}
+		if name == "stable" {
+			kept = []string{pkg.ID, ch.ID, g.ID}
+		}
+	}
+
+	for _, sweep := range []func(string) error{testSweepGroups, testSweepChannels, testSweepPackages} {
+		if err := sweep(""); err != nil {
+			t.Fatalf("err: %s", err)
+		}
+	}
+
+	packages, err := c.ListPackages(appID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	channels, err := c.ListChannels(appID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	groups, err := c.ListGroups(appID)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	if len(packages.Packages) != 1 || packages.Packages[0].ID != kept[0] {
+		t.Errorf("got packages %+v, want only %s", packages.Packages, kept[0])
+	}
+	if len(channels.Channels) != 1 || channels.Channels[0].ID != kept[1] {
+		t.Errorf("got channels %+v, want only %s", channels.Channels, kept[1])
+	}
+	if len(groups.Groups) != 1 || groups.Groups[0].ID != kept[2] {
+		t.Errorf("got groups %+v, want only %s", groups.Groups, kept[2])
+	}
+}
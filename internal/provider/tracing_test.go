@@ -0,0 +1,30 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+	"gotest.tools/assert"
+)
+
+func TestTraceParentContext(t *testing.T) {
+	t.Setenv("TRACEPARENT", "00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01")
+
+	sc := trace.SpanContextFromContext(traceParentContext(context.Background()))
+	assert.Assert(t, sc.IsRemote())
+	assert.Equal(t, sc.TraceID().String(), "0af7651916cd43dd8448eb211c80319c")
+	assert.Equal(t, sc.SpanID().String(), "b7ad6b7169203331")
+
+	// A trace that the context is already part of is kept
+	parent := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{1},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), parent)
+	assert.Assert(t, trace.SpanContextFromContext(traceParentContext(ctx)).Equal(parent))
+
+	t.Setenv("TRACEPARENT", "")
+	assert.Assert(t, !trace.SpanContextFromContext(traceParentContext(context.Background())).IsValid())
+}
@@ -0,0 +1,128 @@
+package snapshot
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// Export walks the given applications, or all of them when none are given,
+// and returns a snapshot of their packages, channels and groups
+func Export(c *nebraska.Client, appIDs []string) (*Snapshot, error) {
+	var apps []nebraska.Application
+	if len(appIDs) == 0 {
+		appsPage, err := c.ListApplications()
+		if err != nil {
+			return nil, fmt.Errorf("couldn't list applications: %w", err)
+		}
+		if appsPage.Count != appsPage.TotalCount {
+			return nil, fmt.Errorf("GET apps returned %d/%d applications. We don't paginate.", appsPage.Count, appsPage.TotalCount)
+		}
+		apps = appsPage.Applications
+	} else {
+		for _, id := range appIDs {
+			app, err := c.GetApplication(id)
+			if err != nil {
+				return nil, fmt.Errorf("couldn't retrieve application %s: %w", id, err)
+			}
+			apps = append(apps, *app)
+		}
+	}
+
+	s := &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Now().UTC(),
+		Endpoint:  c.BaseURL,
+	}
+	for _, app := range apps {
+		a, err := exportApplication(c, app)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't export application %s: %w", app.ID, err)
+		}
+		s.Applications = append(s.Applications, *a)
+	}
+
+	return s, nil
+}
+
+func exportApplication(c *nebraska.Client, app nebraska.Application) (*Application, error) {
+	a := &Application{
+		ID:          app.ID,
+		Name:        app.Name,
+		Description: app.Description,
+		ProductID:   app.ProductID,
+		Packages:    []Package{},
+		Channels:    []Channel{},
+		Groups:      []Group{},
+	}
+
+	packagePage, err := c.ListPackages(app.ID)
+	if err != nil {
+		return nil, err
+	}
+	if packagePage.Count != packagePage.TotalCount {
+		return nil, fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount)
+	}
+	for _, p := range packagePage.Packages {
+		pkg := Package{
+			ID:                p.ID,
+			Version:           p.Version,
+			Arch:              p.Arch.String(),
+			Type:              p.Type.String(),
+			URL:               p.URL,
+			Filename:          p.Filename,
+			Description:       p.Description,
+			Size:              p.Size,
+			Hash:              p.Hash,
+			ChannelsBlacklist: p.ChannelsBlacklist,
+		}
+		if p.FlatcarAction != nil {
+			pkg.FlatcarActionSHA256 = p.FlatcarAction.Sha256
+		}
+		a.Packages = append(a.Packages, pkg)
+	}
+
+	channelPage, err := c.ListChannels(app.ID)
+	if err != nil {
+		return nil, err
+	}
+	if channelPage.Count != channelPage.TotalCount {
+		return nil, fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
+	}
+	for _, ch := range channelPage.Channels {
+		a.Channels = append(a.Channels, Channel{
+			ID:        ch.ID,
+			Name:      ch.Name,
+			Arch:      ch.Arch.String(),
+			Color:     ch.Color,
+			PackageID: ch.PackageID,
+		})
+	}
+
+	groupPage, err := c.ListGroups(app.ID)
+	if err != nil {
+		return nil, err
+	}
+	if groupPage.Count != groupPage.TotalCount {
+		return nil, fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount)
+	}
+	for _, g := range groupPage.Groups {
+		a.Groups = append(a.Groups, Group{
+			ID:                        g.ID,
+			Name:                      g.Name,
+			Description:               g.Description,
+			Track:                     g.Track,
+			ChannelID:                 g.ChannelID,
+			PolicyUpdatesEnabled:      g.PolicyUpdatesEnabled,
+			PolicySafeMode:            g.PolicySafeMode,
+			PolicyOfficeHours:         g.PolicyOfficeHours,
+			PolicyTimezone:            g.PolicyTimezone,
+			PolicyPeriodInterval:      g.PolicyPeriodInterval,
+			PolicyMaxUpdatesPerPeriod: g.PolicyMaxUpdatesPerPeriod,
+			PolicyUpdateTimeout:       g.PolicyUpdateTimeout,
+		})
+	}
+
+	return a, nil
+}
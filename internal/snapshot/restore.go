@@ -0,0 +1,263 @@
+package snapshot
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// IDMapping maps the ids in a snapshot to the ids of the objects they were
+// restored as
+type IDMapping struct {
+	Applications map[string]string `json:"applications"`
+	Packages     map[string]string `json:"packages"`
+	Channels     map[string]string `json:"channels"`
+	Groups       map[string]string `json:"groups"`
+}
+
+// Restore recreates the applications in the snapshot on the server.
+//
+// An application is restored into the application with the same id or product
+// id when there is one, otherwise a new application is created. Objects that
+// already exist with the same natural key (version and arch for packages, name
+// and arch for channels, name and track for groups) are updated to match the
+// snapshot instead of being created again, so restoring is idempotent.
+func Restore(c *nebraska.Client, s *Snapshot) (*IDMapping, error) {
+	ids := &IDMapping{
+		Applications: map[string]string{},
+		Packages:     map[string]string{},
+		Channels:     map[string]string{},
+		Groups:       map[string]string{},
+	}
+	for _, app := range s.Applications {
+		if err := restoreApplication(c, app, ids); err != nil {
+			return ids, fmt.Errorf("couldn't restore application %s: %w", app.ID, err)
+		}
+	}
+
+	return ids, nil
+}
+
+func restoreApplication(c *nebraska.Client, app Application, ids *IDMapping) error {
+	appID, err := targetApplication(c, app)
+	if err != nil {
+		return err
+	}
+	ids.Applications[app.ID] = appID
+
+	// The existing objects are listed before anything is restored, so that
+	// nothing is if a list is truncated, which would make objects that exist
+	// look missing and be restored again
+	packagePage, err := c.ListPackages(appID)
+	if err != nil {
+		return err
+	}
+	if packagePage.Count != packagePage.TotalCount {
+		return fmt.Errorf("GET packages returned %d/%d packages. We don't paginate.", packagePage.Count, packagePage.TotalCount)
+	}
+	channelPage, err := c.ListChannels(appID)
+	if err != nil {
+		return err
+	}
+	if channelPage.Count != channelPage.TotalCount {
+		return fmt.Errorf("GET channels returned %d/%d channels. We don't paginate.", channelPage.Count, channelPage.TotalCount)
+	}
+	groupPage, err := c.ListGroups(appID)
+	if err != nil {
+		return err
+	}
+	if groupPage.Count != groupPage.TotalCount {
+		return fmt.Errorf("GET groups returned %d/%d groups. We don't paginate.", groupPage.Count, groupPage.TotalCount)
+	}
+
+	existingPackages := map[string]string{}
+	for _, p := range packagePage.Packages {
+		existingPackages[p.Version+"/"+p.Arch.String()] = p.ID
+	}
+	// Channels don't exist yet, so the blacklists are set once they do
+	for _, p := range app.Packages {
+		input, err := packageInput(appID, p, []string{})
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.ID, err)
+		}
+		var pkg *nebraska.Package
+		if id, ok := existingPackages[p.Version+"/"+p.Arch]; ok {
+			pkg, err = c.UpdatePackage(appID, id, (*nebraska.UpdatePackageInput)(input))
+		} else {
+			pkg, err = c.AddPackage(appID, input)
+		}
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.ID, err)
+		}
+		ids.Packages[p.ID] = pkg.ID
+	}
+
+	existingChannels := map[string]string{}
+	for _, ch := range channelPage.Channels {
+		existingChannels[ch.Name+"/"+ch.Arch.String()] = ch.ID
+	}
+	for _, ch := range app.Channels {
+		arch, err := nebraska.ArchFromString(ch.Arch)
+		if err != nil {
+			return fmt.Errorf("channel %s: %w", ch.ID, err)
+		}
+		packageID, err := remap(ids.Packages, ch.PackageID)
+		if err != nil {
+			return fmt.Errorf("channel %s: %w", ch.ID, err)
+		}
+		input := &nebraska.AddChannelInput{
+			Name:          ch.Name,
+			Color:         ch.Color,
+			PackageID:     packageID,
+			ApplicationID: appID,
+			Arch:          arch,
+		}
+		var channel *nebraska.Channel
+		if id, ok := existingChannels[ch.Name+"/"+ch.Arch]; ok {
+			channel, err = c.UpdateChannel(appID, id, (*nebraska.UpdateChannelInput)(input))
+		} else {
+			channel, err = c.AddChannel(appID, input)
+		}
+		if err != nil {
+			return fmt.Errorf("channel %s: %w", ch.ID, err)
+		}
+		ids.Channels[ch.ID] = channel.ID
+	}
+
+	for _, p := range app.Packages {
+		if len(p.ChannelsBlacklist) == 0 {
+			continue
+		}
+		blacklist := make([]string, 0, len(p.ChannelsBlacklist))
+		for _, id := range p.ChannelsBlacklist {
+			channelID, err := remap(ids.Channels, id)
+			if err != nil {
+				return fmt.Errorf("package %s: %w", p.ID, err)
+			}
+			blacklist = append(blacklist, channelID)
+		}
+		input, err := packageInput(appID, p, blacklist)
+		if err != nil {
+			return fmt.Errorf("package %s: %w", p.ID, err)
+		}
+		if _, err := c.UpdatePackage(appID, ids.Packages[p.ID], (*nebraska.UpdatePackageInput)(input)); err != nil {
+			return fmt.Errorf("package %s: %w", p.ID, err)
+		}
+	}
+
+	existingGroups := map[string]string{}
+	for _, g := range groupPage.Groups {
+		existingGroups[g.Name+"/"+g.Track] = g.ID
+	}
+	for _, g := range app.Groups {
+		channelID, err := remap(ids.Channels, g.ChannelID)
+		if err != nil {
+			return fmt.Errorf("group %s: %w", g.ID, err)
+		}
+		input := &nebraska.AddGroupInput{
+			Name:                      g.Name,
+			Description:               g.Description,
+			ChannelID:                 channelID,
+			PolicyUpdatesEnabled:      g.PolicyUpdatesEnabled,
+			PolicySafeMode:            g.PolicySafeMode,
+			PolicyOfficeHours:         g.PolicyOfficeHours,
+			PolicyTimezone:            g.PolicyTimezone,
+			PolicyPeriodInterval:      g.PolicyPeriodInterval,
+			PolicyMaxUpdatesPerPeriod: g.PolicyMaxUpdatesPerPeriod,
+			PolicyUpdateTimeout:       g.PolicyUpdateTimeout,
+			Track:                     g.Track,
+		}
+		var group *nebraska.Group
+		if id, ok := existingGroups[g.Name+"/"+g.Track]; ok {
+			group, err = c.UpdateGroup(appID, id, (*nebraska.UpdateGroupInput)(input))
+		} else {
+			group, err = c.AddGroup(appID, input)
+		}
+		if err != nil {
+			return fmt.Errorf("group %s: %w", g.ID, err)
+		}
+		ids.Groups[g.ID] = group.ID
+	}
+
+	return nil
+}
+
+// targetApplication returns the id of the application to restore app into,
+// creating it if there isn't one with the same id or product id
+func targetApplication(c *nebraska.Client, app Application) (string, error) {
+	keys := []string{app.ID}
+	if app.ProductID != "" {
+		keys = append(keys, app.ProductID)
+	}
+	for _, key := range keys {
+		existing, err := c.GetApplication(key)
+		if err == nil {
+			return existing.ID, nil
+		}
+		if !errors.Is(err, nebraska.ErrNotFound) {
+			return "", err
+		}
+	}
+
+	input := &nebraska.AddApplicationInput{
+		Name:        app.Name,
+		Description: app.Description,
+	}
+	if app.ProductID != "" {
+		input.ProductID = &app.ProductID
+	}
+	created, err := c.AddApplication(input)
+	if err != nil {
+		return "", err
+	}
+
+	return created.ID, nil
+}
+
+// packageInput returns the input that restores the package with the
+// blacklist, which mustn't be nil because Nebraska rejects a null one
+func packageInput(appID string, p Package, blacklist []string) (*nebraska.AddPackageInput, error) {
+	arch, err := nebraska.ArchFromString(p.Arch)
+	if err != nil {
+		return nil, err
+	}
+	pkgType, err := nebraska.PackageTypeFromString(p.Type)
+	if err != nil {
+		return nil, err
+	}
+
+	input := &nebraska.AddPackageInput{
+		ApplicationID:     appID,
+		Arch:              arch,
+		ChannelsBlacklist: blacklist,
+		Description:       p.Description,
+		Filename:          p.Filename,
+		Hash:              p.Hash,
+		Size:              p.Size,
+		Type:              pkgType,
+		URL:               p.URL,
+		Version:           p.Version,
+	}
+	if p.FlatcarActionSHA256 != "" {
+		input.FlatcarAction = &nebraska.FlatcarActionInput{
+			Sha256: p.FlatcarActionSHA256,
+		}
+	}
+
+	return input, nil
+}
+
+// remap returns the new id of an object that was referenced by its id in the
+// snapshot
+func remap(ids map[string]string, id string) (string, error) {
+	if id == "" {
+		return "", nil
+	}
+	newID, ok := ids[id]
+	if !ok {
+		return "", fmt.Errorf("references %s, which isn't in the snapshot", id)
+	}
+
+	return newID, nil
+}
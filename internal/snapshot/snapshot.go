@@ -0,0 +1,95 @@
+// Package snapshot exports the configuration of a Nebraska server to a
+// versioned JSON document and restores it on another server.
+package snapshot
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+)
+
+const (
+	// Version is the version of the snapshot format written by Export
+	Version = 1
+)
+
+// Snapshot is the configuration of one or more Nebraska applications
+type Snapshot struct {
+	Version      int           `json:"version"`
+	CreatedAt    time.Time     `json:"created_at"`
+	Endpoint     string        `json:"endpoint"`
+	Applications []Application `json:"applications"`
+}
+
+// Application is an application and everything that belongs to it
+type Application struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ProductID   string    `json:"product_id,omitempty"`
+	Packages    []Package `json:"packages"`
+	Channels    []Channel `json:"channels"`
+	Groups      []Group   `json:"groups"`
+}
+
+// Package is a versioned package
+type Package struct {
+	ID                  string   `json:"id"`
+	Version             string   `json:"version"`
+	Arch                string   `json:"arch"`
+	Type                string   `json:"type"`
+	URL                 string   `json:"url"`
+	Filename            string   `json:"filename"`
+	Description         string   `json:"description"`
+	Size                string   `json:"size"`
+	Hash                string   `json:"hash"`
+	FlatcarActionSHA256 string   `json:"flatcar_action_sha256,omitempty"`
+	ChannelsBlacklist   []string `json:"channels_blacklist,omitempty"`
+}
+
+// Channel is a release channel
+type Channel struct {
+	ID        string `json:"id"`
+	Name      string `json:"name"`
+	Arch      string `json:"arch"`
+	Color     string `json:"color"`
+	PackageID string `json:"package_id,omitempty"`
+}
+
+// Group is a group of instances
+type Group struct {
+	ID                        string `json:"id"`
+	Name                      string `json:"name"`
+	Description               string `json:"description"`
+	Track                     string `json:"track"`
+	ChannelID                 string `json:"channel_id,omitempty"`
+	PolicyUpdatesEnabled      bool   `json:"policy_updates_enabled"`
+	PolicySafeMode            bool   `json:"policy_safe_mode"`
+	PolicyOfficeHours         bool   `json:"policy_office_hours"`
+	PolicyTimezone            string `json:"policy_timezone"`
+	PolicyPeriodInterval      string `json:"policy_period_interval"`
+	PolicyMaxUpdatesPerPeriod int    `json:"policy_max_updates_per_period"`
+	PolicyUpdateTimeout       string `json:"policy_update_timeout"`
+}
+
+// Read decodes a snapshot and checks that its version is supported
+func Read(r io.Reader) (*Snapshot, error) {
+	s := &Snapshot{}
+	if err := json.NewDecoder(r).Decode(s); err != nil {
+		return nil, fmt.Errorf("couldn't decode snapshot: %w", err)
+	}
+	if s.Version != Version {
+		return nil, fmt.Errorf("unsupported snapshot version %d, expected %d", s.Version, Version)
+	}
+
+	return s, nil
+}
+
+// Write encodes the snapshot as indented JSON
+func (s *Snapshot) Write(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+
+	return enc.Encode(s)
+}
@@ -0,0 +1,206 @@
+package snapshot
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest"
+	"gotest.tools/assert"
+)
+
+func TestSnapshotReadWrite(t *testing.T) {
+	s := &Snapshot{
+		Version:   Version,
+		CreatedAt: time.Date(2021, 1, 2, 3, 4, 5, 0, time.UTC),
+		Endpoint:  "http://nebraska:8000",
+		Applications: []Application{
+			{
+				ID:   "e96281a6-d1af-4bde-9a0a-97b76e56dc57",
+				Name: "Flatcar Container Linux",
+				Packages: []Package{
+					{ID: "p1", Version: "2905.2.0", Arch: "amd64", Type: "flatcar", FlatcarActionSHA256: "sha256", ChannelsBlacklist: []string{"c1"}},
+				},
+				Channels: []Channel{
+					{ID: "c1", Name: "stable", Arch: "amd64", Color: "#000000", PackageID: "p1"},
+				},
+				Groups: []Group{
+					{ID: "g1", Name: "Stable", Track: "stable", ChannelID: "c1", PolicyMaxUpdatesPerPeriod: 1},
+				},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	assert.NilError(t, s.Write(&buf))
+
+	got, err := Read(&buf)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, s)
+}
+
+func TestSnapshotReadUnsupportedVersion(t *testing.T) {
+	_, err := Read(strings.NewReader(`{"version": 2}`))
+	assert.ErrorContains(t, err, "unsupported snapshot version 2")
+}
+
+func TestRemap(t *testing.T) {
+	ids := map[string]string{"old": "new"}
+
+	id, err := remap(ids, "old")
+	assert.NilError(t, err)
+	assert.Equal(t, id, "new")
+
+	id, err = remap(ids, "")
+	assert.NilError(t, err)
+	assert.Equal(t, id, "")
+
+	_, err = remap(ids, "missing")
+	assert.ErrorContains(t, err, "isn't in the snapshot")
+}
+
+func TestRestore(t *testing.T) {
+	want := testSnapshot(t)
+	appID := nebraska.FlatcarApplicationID
+
+	dst := nebraskatest.NewServer()
+	defer dst.Close()
+	// Restoring twice updates what the first restore created
+	for range 2 {
+		ids, err := Restore(dst.Client(), want)
+		assert.NilError(t, err)
+
+		got, err := Export(dst.Client(), []string{appID})
+		assert.NilError(t, err)
+		assert.DeepEqual(t, testRestoredIDs(got, ids), want.Applications)
+	}
+}
+
+func TestRestoreTruncatedList(t *testing.T) {
+	want := testSnapshot(t)
+	appID := nebraska.FlatcarApplicationID
+
+	dst := nebraskatest.NewServer()
+	defer dst.Close()
+	_, err := Restore(dst.Client(), want)
+	assert.NilError(t, err)
+
+	// A server that returns a page of the packages, so the ones that aren't
+	// on it look missing
+	u, err := url.Parse(dst.URL)
+	assert.NilError(t, err)
+	proxy := httputil.NewSingleHostReverseProxy(u)
+	truncated := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasSuffix(r.URL.Path, "/packages") {
+			q := r.URL.Query()
+			q.Set("perpage", "1")
+			r.URL.RawQuery = q.Encode()
+		}
+		proxy.ServeHTTP(w, r)
+	}))
+	defer truncated.Close()
+
+	_, err = Restore(nebraska.New(truncated.URL, "nebraskatest", "", "", ""), want)
+	assert.ErrorContains(t, err, "GET packages returned 1/2 packages")
+
+	got, err := Export(dst.Client(), []string{appID})
+	assert.NilError(t, err)
+	assert.Equal(t, len(got.Applications[0].Packages), 2)
+}
+
+// testSnapshot returns a snapshot of a server with packages, channels, a
+// blacklist and a group
+func testSnapshot(t *testing.T) *Snapshot {
+	t.Helper()
+
+	src := nebraskatest.NewServer()
+	defer src.Close()
+	c := src.Client()
+	appID := nebraska.FlatcarApplicationID
+
+	var packages []*nebraska.Package
+	for _, version := range []string{"3510.2.0", "3602.2.0"} {
+		p, err := c.AddPackage(appID, &nebraska.AddPackageInput{
+			Arch:              nebraska.ArchAMD64,
+			ChannelsBlacklist: []string{},
+			Type:              nebraska.PackageTypeFlatcar,
+			URL:               "https://update.release.flatcar-linux.net/",
+			Version:           version,
+			FlatcarAction:     &nebraska.FlatcarActionInput{Sha256: "sha256-" + version},
+		})
+		assert.NilError(t, err)
+		packages = append(packages, p)
+	}
+	stable, err := c.AddChannel(appID, &nebraska.AddChannelInput{Name: "stable", Arch: nebraska.ArchAMD64, Color: "#000000", PackageID: packages[0].ID})
+	assert.NilError(t, err)
+	_, err = c.AddChannel(appID, &nebraska.AddChannelInput{Name: "beta", Arch: nebraska.ArchAMD64, Color: "#ffffff", PackageID: packages[1].ID})
+	assert.NilError(t, err)
+	_, err = c.UpdatePackage(appID, packages[1].ID, &nebraska.UpdatePackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{stable.ID},
+		Type:              nebraska.PackageTypeFlatcar,
+		URL:               "https://update.release.flatcar-linux.net/",
+		Version:           "3602.2.0",
+		FlatcarAction:     &nebraska.FlatcarActionInput{Sha256: "sha256-3602.2.0"},
+	})
+	assert.NilError(t, err)
+	_, err = c.AddGroup(appID, &nebraska.AddGroupInput{
+		Name:                      "Stable",
+		ChannelID:                 stable.ID,
+		PolicyTimezone:            "UTC",
+		PolicyPeriodInterval:      "1 hours",
+		PolicyMaxUpdatesPerPeriod: 1,
+		PolicyUpdateTimeout:       "1 days",
+		Track:                     "stable",
+	})
+	assert.NilError(t, err)
+
+	s, err := Export(c, []string{appID})
+	assert.NilError(t, err)
+
+	return s
+}
+
+// testRestoredIDs returns the applications of a snapshot of restored objects
+// with the ids they were restored from
+func testRestoredIDs(s *Snapshot, ids *IDMapping) []Application {
+	old := func(m map[string]string, id string) string {
+		for k, v := range m {
+			if v == id {
+				return k
+			}
+		}
+		return id
+	}
+
+	apps := s.Applications
+	for i := range apps {
+		a := &apps[i]
+		a.ID = old(ids.Applications, a.ID)
+		for j := range a.Packages {
+			p := &a.Packages[j]
+			p.ID = old(ids.Packages, p.ID)
+			for k, id := range p.ChannelsBlacklist {
+				p.ChannelsBlacklist[k] = old(ids.Channels, id)
+			}
+		}
+		for j := range a.Channels {
+			ch := &a.Channels[j]
+			ch.ID = old(ids.Channels, ch.ID)
+			ch.PackageID = old(ids.Packages, ch.PackageID)
+		}
+		for j := range a.Groups {
+			g := &a.Groups[j]
+			g.ID = old(ids.Groups, g.ID)
+			g.ChannelID = old(ids.Channels, g.ChannelID)
+		}
+	}
+
+	return apps
+}
@@ -4,8 +4,9 @@ import (
 	"context"
 	"flag"
 	"log"
+	"os"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/plugin"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov5/tf5server"
 	"github.com/utilitywarehouse/terraform-provider-nebraska/internal/provider"
 )
 
@@ -29,20 +30,41 @@ var (
 )
 
 func main() {
+	if len(os.Args) > 1 {
+		if cmd, ok := commands[os.Args[1]]; ok {
+			if err := cmd(os.Args[2:]); err != nil {
+				log.Fatal(err.Error())
+			}
+			return
+		}
+	}
+
 	var debugMode bool
 
 	flag.BoolVar(&debugMode, "debug", false, "set to true to run the provider with support for debuggers like delve")
 	flag.Parse()
 
-	opts := &plugin.ServeOpts{ProviderFunc: provider.New(version)}
+	ctx := context.Background()
+	shutdownTracing, err := provider.StartTracing(ctx, version)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
+	providerServer, err := provider.ProviderServer(ctx, version)
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 
+	var serveOpts []tf5server.ServeOpt
 	if debugMode {
-		err := plugin.Debug(context.Background(), "registry.terraform.io/utilitywarehouse/nebraska", opts)
-		if err != nil {
-			log.Fatal(err.Error())
-		}
-		return
+		serveOpts = append(serveOpts, tf5server.WithManagedDebug())
 	}
 
-	plugin.Serve(opts)
+	err = tf5server.Serve("registry.terraform.io/utilitywarehouse/nebraska", providerServer, serveOpts...)
+	// Export the spans that are still buffered before the process exits
+	if err := shutdownTracing(ctx); err != nil {
+		log.Printf("[WARN] Couldn't export traces: %s", err)
+	}
+	if err != nil {
+		log.Fatal(err.Error())
+	}
 }
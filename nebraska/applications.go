@@ -0,0 +1,58 @@
+package nebraska
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// GetApplication retrieves an application by its id or product id
+func (c *Client) GetApplication(id string) (*Application, error) {
+	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s", id), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Application{}
+	if err := c.do(req, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// ListApplications lists all the applications
+func (c *Client) ListApplications() (*AppsPage, error) {
+	req, err := c.newRequest(http.MethodGet, "/api/apps?page=1&perpage=10000", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &AppsPage{}
+	if err := c.do(req, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// AddApplicationInput are the supported arguments when adding an application
+type AddApplicationInput struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	ProductID   *string `json:"product_id"`
+}
+
+// AddApplication adds a new application
+func (c *Client) AddApplication(input *AddApplicationInput) (*Application, error) {
+	req, err := c.newRequest(http.MethodPost, "/api/apps", input)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &Application{}
+	if err := c.do(req, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
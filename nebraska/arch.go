@@ -1,13 +1,34 @@
 package nebraska
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Arch is an architecture that Nebraska supports. Nebraska's API represents
+// it as an integer, and everything else by its name.
+type Arch uint
+
+const (
+	// ArchAll is a package or channel for all architectures
+	ArchAll Arch = iota
+	// ArchAMD64 is x86-64
+	ArchAMD64
+	// ArchAArch64 is 64-bit ARM
+	ArchAArch64
+	// ArchX86 is 32-bit x86
+	ArchX86
+)
 
 var (
 	// ErrInvalidArch is a custom error returned when an unsupported arch is
 	// requested
 	ErrInvalidArch = errors.New("nebraska: invalid/unsupported arch")
 
-	// ValidArchs are the archs that Nebraska supports
+	// ValidArchs are the archs that Nebraska supports, in the order of their
+	// values
 	// https://github.com/kinvolk/nebraska/blob/main/backend/pkg/api/arch.go#L37-L43
 	ValidArchs = []string{
 		"all",
@@ -15,4 +36,85 @@ var (
 		"aarch64",
 		"x86",
 	}
+
+	// archAliases maps the names that other tools use for an arch to the
+	// name that Nebraska uses
+	archAliases = map[string]string{
+		"x86_64": "amd64",
+		"x64":    "amd64",
+		"arm64":  "aarch64",
+		"armv8":  "aarch64",
+		"386":    "x86",
+		"i386":   "x86",
+		"i686":   "x86",
+	}
 )
+
+// String returns the name of the arch, or Arch(n) if it isn't one that
+// Nebraska supports
+func (a Arch) String() string {
+	if a.IsValid() {
+		return ValidArchs[a]
+	}
+
+	return fmt.Sprintf("Arch(%d)", uint(a))
+}
+
+// IsValid returns true if Nebraska supports the arch
+func (a Arch) IsValid() bool {
+	return int(a) < len(ValidArchs)
+}
+
+// MarshalJSON encodes the arch as an integer, like Nebraska
+func (a Arch) MarshalJSON() ([]byte, error) {
+	return json.Marshal(uint(a))
+}
+
+// UnmarshalJSON decodes the arch from an integer, like Nebraska sends it, or
+// from its name
+func (a *Arch) UnmarshalJSON(data []byte) error {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		arch, err := ArchFromString(name)
+		if err != nil {
+			return fmt.Errorf("%w: %q", err, name)
+		}
+		*a = arch
+		return nil
+	}
+
+	var i uint
+	if err := json.Unmarshal(data, &i); err != nil {
+		return fmt.Errorf("nebraska: arch must be an integer or a name: %w", err)
+	}
+	*a = Arch(i)
+
+	return nil
+}
+
+// ArchFromString returns the arch with the name that Nebraska uses for it
+func ArchFromString(s string) (Arch, error) {
+	for i, a := range ValidArchs {
+		if s == a {
+			return Arch(i), nil
+		}
+	}
+
+	return ArchAll, ErrInvalidArch
+}
+
+// NormalizeArch returns the name that Nebraska uses for the arch, which may
+// be given as it's known to Go, Docker, uname and the like
+func NormalizeArch(s string) (string, error) {
+	arch := strings.ToLower(strings.TrimSpace(s))
+	if alias, ok := archAliases[arch]; ok {
+		arch = alias
+	}
+	for _, a := range ValidArchs {
+		if arch == a {
+			return arch, nil
+		}
+	}
+
+	return "", ErrInvalidArch
+}
@@ -0,0 +1,57 @@
+package nebraska
+
+import (
+	"encoding/json"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestNormalizeArch(t *testing.T) {
+	for in, want := range map[string]string{
+		"amd64":   "amd64",
+		"x86_64":  "amd64",
+		"ARM64":   "aarch64",
+		"aarch64": "aarch64",
+		"i686":    "x86",
+		" all ":   "all",
+	} {
+		got, err := NormalizeArch(in)
+		assert.NilError(t, err)
+		assert.Equal(t, got, want, in)
+	}
+
+	_, err := NormalizeArch("riscv64")
+	assert.Equal(t, err, ErrInvalidArch)
+}
+
+func TestArchJSON(t *testing.T) {
+	data, err := json.Marshal(struct {
+		Arch Arch `json:"arch"`
+	}{ArchAArch64})
+	assert.NilError(t, err)
+	assert.Equal(t, string(data), `{"arch":2}`)
+
+	for in, want := range map[string]Arch{
+		`0`:       ArchAll,
+		`1`:       ArchAMD64,
+		`3`:       ArchX86,
+		`"amd64"`: ArchAMD64,
+	} {
+		var got Arch
+		assert.NilError(t, json.Unmarshal([]byte(in), &got))
+		assert.Equal(t, got, want, in)
+	}
+
+	var a Arch
+	assert.ErrorContains(t, json.Unmarshal([]byte(`"riscv64"`), &a), "invalid/unsupported arch")
+	assert.ErrorContains(t, json.Unmarshal([]byte(`-1`), &a), "must be an integer or a name")
+
+	assert.Equal(t, ArchAMD64.String(), "amd64")
+	assert.Equal(t, Arch(9).String(), "Arch(9)")
+	arch, err := ArchFromString("aarch64")
+	assert.NilError(t, err)
+	assert.Equal(t, arch, ArchAArch64)
+	_, err = ArchFromString("arm64")
+	assert.Equal(t, err, ErrInvalidArch)
+}
@@ -2,12 +2,17 @@ package nebraska
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
 	"io/ioutil"
 	"net/http"
 	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/trace"
 )
 
 const (
@@ -31,6 +36,23 @@ type Client struct {
 	username    string
 	password    string
 	bearerToken string
+
+	ctx            context.Context
+	limiter        *Limiter
+	tracerProvider trace.TracerProvider
+	logBodies      bool
+	server         *serverState
+}
+
+// serverState is what the client knows about the server, which it shares
+// with the clients returned by WithContext
+type serverState struct {
+	configOnce sync.Once
+	config     *serverConfig
+	configErr  error
+
+	mu           sync.Mutex
+	capabilities *Capabilities
 }
 
 // New returns a new client for the given Nebraska server URL
@@ -43,8 +65,66 @@ func New(baseURL string, userAgent string, username string, password string, bea
 		username:    username,
 		password:    password,
 		bearerToken: bearerToken,
+		ctx:         context.Background(),
+		server:      &serverState{},
+	}
+
+}
+
+// WithContext returns a copy of the client that sends requests with the
+// context, which can cancel them while they're sent or waiting for the
+// limiter
+func (c *Client) WithContext(ctx context.Context) *Client {
+	cp := *c
+	cp.ctx = ctx
+
+	return &cp
+}
+
+// SetLimiter limits the requests of the client with the limiter, which can be
+// shared with other clients
+func (c *Client) SetLimiter(l *Limiter) {
+	c.limiter = l
+}
+
+// roundTrip sends the request within the limits of the limiter, if there is
+// one, and traces and logs it. The response body must be closed.
+func (c *Client) roundTrip(req *http.Request) (resp *http.Response, err error) {
+	req, span := c.startSpan(req)
+	defer func() { endSpan(span, resp, err) }()
+
+	release := func() {}
+	if c.limiter != nil {
+		if release, err = c.limiter.wait(req); err != nil {
+			return nil, err
+		}
 	}
+	c.logRequest(req)
+	start := time.Now()
+	resp, err = c.c.Do(req)
+	c.logResponse(req, resp, err, time.Since(start))
+	if err != nil {
+		release()
+		return nil, err
+	}
+	resp.Body = &releasingBody{ReadCloser: resp.Body, release: release}
+
+	return resp, nil
+}
 
+// releasingBody releases a request's slot in the limiter when its response
+// body is closed
+type releasingBody struct {
+	io.ReadCloser
+	release func()
+	once    sync.Once
+}
+
+func (b *releasingBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(b.release)
+
+	return err
 }
 
 func (c *Client) newRequest(method, path string, body interface{}) (*http.Request, error) {
@@ -57,7 +137,7 @@ func (c *Client) newRequest(method, path string, body interface{}) (*http.Reques
 	if !strings.HasPrefix(path, "/") {
 		path = "/" + path
 	}
-	req, err := http.NewRequest(method, fmt.Sprintf("%s%s", c.BaseURL, path), &buf)
+	req, err := http.NewRequestWithContext(c.ctx, method, fmt.Sprintf("%s%s", c.BaseURL, path), &buf)
 	if err != nil {
 		return nil, err
 	}
@@ -72,8 +152,19 @@ func (c *Client) newRequest(method, path string, body interface{}) (*http.Reques
 	return req, nil
 }
 
+// do sends the request to a server that the client supports, and decodes the
+// response into data after adapting it to the latest version of the API
 func (c *Client) do(req *http.Request, data interface{}) error {
-	resp, err := c.c.Do(req)
+	adapters, err := c.serverAdapters()
+	if err != nil {
+		return err
+	}
+
+	return c.send(req, data, adapters)
+}
+
+func (c *Client) send(req *http.Request, data interface{}, adapters []serverAdapter) error {
+	resp, err := c.roundTrip(req)
 	if err != nil {
 		return err
 	}
@@ -98,7 +189,12 @@ func (c *Client) do(req *http.Request, data interface{}) error {
 	if err != nil {
 		return err
 	}
-	if len(body) > 0 {
+	for _, a := range adapters {
+		if body, err = a.response(req, body); err != nil {
+			return err
+		}
+	}
+	if data != nil && len(body) > 0 {
 		if err := json.Unmarshal(body, &data); err != nil {
 			return err
 		}
@@ -3,11 +3,17 @@ package nebraska
 import (
 	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"io/fs"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 	"testing"
 
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska/nebraskatest/recorder"
 	"gotest.tools/assert"
 )
 
@@ -16,11 +22,57 @@ var (
 )
 
 func testClientServer(username string, password string, bearer string, handler func(w http.ResponseWriter, r *http.Request)) (*Client, *httptest.Server) {
-	s := httptest.NewServer(http.HandlerFunc(handler))
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/config" {
+			w.Write([]byte(`{"nebraska_version":"2.9.0"}`))
+			return
+		}
+		handler(w, r)
+	}))
 	c := New(s.URL, testUserAgent, username, password, bearer)
 	return c, s
 }
 
+// testRecordedClient returns a client for the Nebraska server at
+// NEBRASKA_ENDPOINT which, depending on NEBRASKA_TEST_MODE, records the
+// requests of the test into testdata/fixtures, or replays them without a
+// server. The test fails if it hasn't been recorded.
+func testRecordedClient(t *testing.T) *Client {
+	t.Helper()
+
+	mode, err := recorder.ModeFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	endpoint := os.Getenv("NEBRASKA_ENDPOINT")
+	if mode != recorder.ModeReplay && endpoint == "" {
+		t.Fatalf("NEBRASKA_ENDPOINT must be set to run %s in %s mode", t.Name(), mode)
+	}
+	c := New(endpoint, testUserAgent, os.Getenv("NEBRASKA_USERNAME"), os.Getenv("NEBRASKA_PASSWORD"), os.Getenv("NEBRASKA_BEARER_TOKEN"))
+	if mode == recorder.ModeLive {
+		return c
+	}
+
+	rec, err := recorder.New(filepath.Join("testdata", "fixtures", t.Name()+".json"), mode, http.DefaultTransport)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Fatalf("%s hasn't been recorded, run it with NEBRASKA_TEST_MODE=record", t.Name())
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		if err := rec.Close(); err != nil {
+			t.Error(err)
+		}
+	})
+	if mode == recorder.ModeReplay {
+		c.BaseURL = "http://nebraska.invalid"
+	}
+	c.c = &http.Client{Transport: rec}
+
+	return c
+}
+
 type testSchema struct {
 	Name       string   `json:"name"`
 	Parameters []string `json:"parameters"`
@@ -75,3 +127,233 @@ func TestClientRequest(t *testing.T) {
 
 	assert.DeepEqual(t, data, expectedRespBody)
 }
+
+func TestClientServerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		version string
+		err     error
+		list    string
+	}{
+		{version: "2.7.0", list: `[{"id":"a"}]`},
+		{version: "v2.8.0", list: `{"applications":[{"id":"a"}],"count":1,"totalCount":1}`},
+		{version: "2.6.1", err: ErrUnsupportedServerVersion},
+		{version: "3.0.0", err: ErrUnsupportedServerVersion},
+	} {
+		t.Run(tc.version, func(t *testing.T) {
+			configRequests := 0
+			s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/config":
+					configRequests++
+					json.NewEncoder(w).Encode(map[string]string{"nebraska_version": tc.version})
+				case "/api/apps":
+					w.Write([]byte(tc.list))
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer s.Close()
+			c := New(s.URL, testUserAgent, "", "", "")
+
+			for range 2 {
+				apps, err := c.ListApplications()
+				if tc.err != nil {
+					assert.Assert(t, errors.Is(err, tc.err), "got %v", err)
+					continue
+				}
+				assert.NilError(t, err)
+				assert.Equal(t, apps.TotalCount, 1)
+				assert.Equal(t, apps.Applications[0].ID, "a")
+			}
+			assert.Equal(t, configRequests, 1)
+		})
+	}
+}
+
+func TestClientServerVersionFailure(t *testing.T) {
+	var configRequests atomic.Int32
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/config" {
+			configRequests.Add(1)
+		}
+		http.Error(w, http.StatusText(http.StatusServiceUnavailable), http.StatusServiceUnavailable)
+	}))
+	defer s.Close()
+	c := New(s.URL, testUserAgent, "", "", "")
+
+	// The requests wait for the first one to ask for the version, and fail
+	// with its error without asking again
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() {
+			_, err := c.ListApplications()
+			assert.ErrorContains(t, err, "couldn't detect the server version")
+		})
+	}
+	wg.Wait()
+	_, err := c.ListApplications()
+	assert.ErrorContains(t, err, "couldn't detect the server version")
+	assert.Equal(t, configRequests.Load(), int32(1))
+}
+
+func TestClientApplications(t *testing.T) {
+	c := testRecordedClient(t)
+
+	app, err := c.GetApplication(FlatcarApplicationID)
+	assert.NilError(t, err)
+	assert.Equal(t, app.ID, FlatcarApplicationID)
+
+	apps, err := c.ListApplications()
+	assert.NilError(t, err)
+	assert.Equal(t, apps.Count, apps.TotalCount)
+	found := false
+	for _, a := range apps.Applications {
+		found = found || a.ID == FlatcarApplicationID
+	}
+	assert.Assert(t, found)
+
+	_, err = c.GetApplication("00000000-0000-0000-0000-000000000000")
+	assert.Equal(t, err, ErrNotFound)
+}
+
+func TestClientPackages(t *testing.T) {
+	c := testRecordedClient(t)
+	appID := FlatcarApplicationID
+
+	pkg, err := c.AddPackage(appID, &AddPackageInput{
+		ApplicationID:     appID,
+		Arch:              ArchAMD64,
+		Type:              PackageTypeFlatcar,
+		URL:               "http://fake-address/",
+		Version:           "0.0.100",
+		ChannelsBlacklist: []string{},
+		FlatcarAction:     &FlatcarActionInput{Sha256: "sha256"},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, pkg.Version, "0.0.100")
+	assert.Equal(t, pkg.FlatcarAction.Sha256, "sha256")
+
+	packages, err := c.SearchPackages(appID, "0.0.100")
+	assert.NilError(t, err)
+	assert.Equal(t, packages.TotalCount, 1)
+	assert.Equal(t, packages.Packages[0].ID, pkg.ID)
+
+	updated, err := c.UpdatePackage(appID, pkg.ID, &UpdatePackageInput{
+		ApplicationID:     appID,
+		Arch:              ArchAMD64,
+		Description:       "updated",
+		Type:              PackageTypeFlatcar,
+		URL:               "http://fake-address/",
+		Version:           "0.0.100",
+		ChannelsBlacklist: []string{},
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, updated.Description, "updated")
+
+	assert.NilError(t, c.DeletePackage(appID, pkg.ID))
+	_, err = c.GetPackage(appID, pkg.ID)
+	assert.Equal(t, err, ErrNotFound)
+}
+
+func TestClientChannels(t *testing.T) {
+	c := testRecordedClient(t)
+	appID := FlatcarApplicationID
+
+	pkg, err := c.AddPackage(appID, &AddPackageInput{
+		ApplicationID:     appID,
+		Arch:              ArchAMD64,
+		Type:              PackageTypeFlatcar,
+		URL:               "http://fake-address/",
+		Version:           "0.0.101",
+		ChannelsBlacklist: []string{},
+	})
+	assert.NilError(t, err)
+	defer c.DeletePackage(appID, pkg.ID)
+
+	ch, err := c.AddChannel(appID, &AddChannelInput{
+		Name:          "client-test",
+		Color:         "#1fbb86",
+		Arch:          ArchAMD64,
+		ApplicationID: appID,
+		PackageID:     pkg.ID,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, ch.PackageID, pkg.ID)
+
+	channels, err := c.ListChannels(appID)
+	assert.NilError(t, err)
+	found := false
+	for _, c := range channels.Channels {
+		found = found || c.ID == ch.ID
+	}
+	assert.Assert(t, found)
+
+	ch, err = c.UpdateChannel(appID, ch.ID, &UpdateChannelInput{
+		Name:          "client-test",
+		Color:         "#000000",
+		Arch:          ArchAMD64,
+		ApplicationID: appID,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, ch.Color, "#000000")
+	assert.Equal(t, ch.PackageID, "")
+
+	assert.NilError(t, c.DeleteChannel(appID, ch.ID))
+	_, err = c.GetChannel(appID, ch.ID)
+	assert.Equal(t, err, ErrNotFound)
+}
+
+func TestClientGroups(t *testing.T) {
+	c := testRecordedClient(t)
+	appID := FlatcarApplicationID
+
+	ch, err := c.AddChannel(appID, &AddChannelInput{
+		Name:          "client-test-groups",
+		Color:         "#1fbb86",
+		Arch:          ArchAMD64,
+		ApplicationID: appID,
+	})
+	assert.NilError(t, err)
+	defer c.DeleteChannel(appID, ch.ID)
+
+	group, err := c.AddGroup(appID, &AddGroupInput{
+		Name:                      "client-test",
+		ChannelID:                 ch.ID,
+		PolicyTimezone:            "Europe/London",
+		PolicyPeriodInterval:      "15 minutes",
+		PolicyMaxUpdatesPerPeriod: 2,
+		PolicyUpdateTimeout:       "60 minutes",
+		Track:                     "client-test",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, group.ChannelID, ch.ID)
+
+	stats, err := c.GetGroupInstanceStats(appID, group.ID, "7d")
+	assert.NilError(t, err)
+	assert.Equal(t, stats.Total, 0)
+
+	groups, err := c.ListGroups(appID)
+	assert.NilError(t, err)
+	found := false
+	for _, g := range groups.Groups {
+		found = found || g.ID == group.ID
+	}
+	assert.Assert(t, found)
+
+	group, err = c.UpdateGroup(appID, group.ID, &UpdateGroupInput{
+		Name:                      "client-test",
+		ChannelID:                 ch.ID,
+		PolicyUpdatesEnabled:      true,
+		PolicyTimezone:            "Europe/London",
+		PolicyPeriodInterval:      "15 minutes",
+		PolicyMaxUpdatesPerPeriod: 2,
+		PolicyUpdateTimeout:       "60 minutes",
+		Track:                     "client-test",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, group.PolicyUpdatesEnabled, true)
+
+	assert.NilError(t, c.DeleteGroup(appID, group.ID))
+	_, err = c.GetGroup(appID, group.ID)
+	assert.Equal(t, err, ErrNotFound)
+}
@@ -0,0 +1,244 @@
+package nebraska
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"path"
+
+	"github.com/blang/semver/v4"
+)
+
+const (
+	// SupportedServerVersions is the range of Nebraska releases that the
+	// client supports
+	SupportedServerVersions = ">=2.7.0 <3.0.0"
+)
+
+var (
+	// ErrUnsupportedServerVersion is returned by every request to a Nebraska
+	// server whose version the client doesn't support
+	ErrUnsupportedServerVersion = errors.New("nebraska: unsupported server version")
+
+	supportedServerVersions = semver.MustParseRange(SupportedServerVersions)
+
+	// featureVersions are the releases of Nebraska that introduced each
+	// feature
+	featureVersions = map[Feature]semver.Version{
+		FeatureInstanceStatsDuration: semver.MustParse("2.8.0"),
+		FeaturePackageSearch:         semver.MustParse("2.8.0"),
+	}
+
+	// serverAdapters adapt the client to the releases of Nebraska whose API
+	// differs from the latest one
+	serverAdapters = []serverAdapter{
+		{
+			name:     "legacy lists",
+			versions: semver.MustParseRange("<2.8.0"),
+			response: adaptLegacyList,
+		},
+	}
+
+	// legacyListKeys are the keys of the pages that list endpoints respond
+	// with, by the last element of their path
+	legacyListKeys = map[string]string{
+		"apps":     "applications",
+		"channels": "channels",
+		"groups":   "groups",
+		"packages": "packages",
+	}
+)
+
+// Feature is a feature of the Nebraska API that not every supported release
+// has
+type Feature string
+
+const (
+	// FeatureInstanceStatsDuration is the duration that a group's instance
+	// stats can be limited to
+	FeatureInstanceStatsDuration Feature = "instance_stats_duration"
+	// FeaturePackageSearch is searching the packages of an application by
+	// version
+	FeaturePackageSearch Feature = "package_search"
+)
+
+// MinimumVersion returns the release of Nebraska that introduced the feature
+func (f Feature) MinimumVersion() semver.Version {
+	return featureVersions[f]
+}
+
+// Capabilities are the version of a Nebraska server and what it supports
+type Capabilities struct {
+	Version semver.Version
+	// AuthMode is how the server authenticates users: noop, github or oidc
+	AuthMode string
+	Features map[Feature]bool
+}
+
+// Supports returns true if the server supports the feature
+func (c *Capabilities) Supports(f Feature) bool {
+	return c.Features[f]
+}
+
+// serverConfig is the part of the response from Nebraska's /config endpoint
+// that the client uses
+type serverConfig struct {
+	NebraskaVersion string `json:"nebraska_version"`
+	AuthMode        string `json:"auth_mode"`
+
+	version semver.Version
+}
+
+// serverAdapter rewrites the responses of the Nebraska releases in versions
+// into the format of the latest one
+type serverAdapter struct {
+	name     string
+	versions semver.Range
+	response func(req *http.Request, body []byte) ([]byte, error)
+}
+
+// ServerVersion returns the version of the Nebraska server. It's requested
+// from the server the first time and remembered after that.
+func (c *Client) ServerVersion() (semver.Version, error) {
+	config, err := c.serverConfig()
+	if err != nil {
+		return semver.Version{}, err
+	}
+
+	return config.version, nil
+}
+
+// Capabilities checks that the Nebraska server is healthy and supported by
+// the client, and returns its version, auth mode and the features that it
+// supports. The server is only asked for them the first time.
+func (c *Client) Capabilities() (*Capabilities, error) {
+	c.server.mu.Lock()
+	capabilities := c.server.capabilities
+	c.server.mu.Unlock()
+	if capabilities != nil {
+		return capabilities, nil
+	}
+
+	config, err := c.serverConfig()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkServerVersion(config.version); err != nil {
+		return nil, err
+	}
+	req, err := c.newRequest(http.MethodGet, "/health", nil)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.send(req, nil, nil); err != nil {
+		return nil, fmt.Errorf("nebraska: server isn't healthy: %w", err)
+	}
+
+	capabilities = &Capabilities{
+		Version:  config.version,
+		AuthMode: config.AuthMode,
+		Features: map[Feature]bool{},
+	}
+	for f, v := range featureVersions {
+		capabilities.Features[f] = config.version.GTE(v)
+	}
+	c.server.mu.Lock()
+	c.server.capabilities = capabilities
+	c.server.mu.Unlock()
+
+	return capabilities, nil
+}
+
+// supports returns true if the server supports the feature. Servers whose
+// version can't be detected don't support any.
+func (c *Client) supports(f Feature) bool {
+	v, err := c.ServerVersion()
+
+	return err == nil && v.GTE(f.MinimumVersion())
+}
+
+// serverConfig returns the configuration of the server, which is requested
+// the first time and remembered after that, as is the error if the request
+// fails. Concurrent requests wait for the first one to get it.
+func (c *Client) serverConfig() (*serverConfig, error) {
+	c.server.configOnce.Do(func() {
+		c.server.config, c.server.configErr = c.getServerConfig()
+	})
+
+	return c.server.config, c.server.configErr
+}
+
+// getServerConfig requests the configuration of the server
+func (c *Client) getServerConfig() (*serverConfig, error) {
+	req, err := c.newRequest(http.MethodGet, "/config", nil)
+	if err != nil {
+		return nil, err
+	}
+	config := &serverConfig{}
+	if err := c.send(req, config, nil); err != nil {
+		return nil, fmt.Errorf("nebraska: couldn't detect the server version: %w", err)
+	}
+	config.version, err = semver.ParseTolerant(config.NebraskaVersion)
+	if err != nil {
+		return nil, fmt.Errorf("%w %q: %s", ErrUnsupportedServerVersion, config.NebraskaVersion, err)
+	}
+
+	return config, nil
+}
+
+// checkServerVersion returns an error if the client doesn't support the
+// version of the server
+func checkServerVersion(v semver.Version) error {
+	if !supportedServerVersions(v) {
+		return fmt.Errorf("%w %s: the client supports Nebraska %s", ErrUnsupportedServerVersion, v, SupportedServerVersions)
+	}
+
+	return nil
+}
+
+// serverAdapters returns the adapters for the version of the server, or an
+// error if the client doesn't support it
+func (c *Client) serverAdapters() ([]serverAdapter, error) {
+	v, err := c.ServerVersion()
+	if err != nil {
+		return nil, err
+	}
+	if err := checkServerVersion(v); err != nil {
+		return nil, err
+	}
+
+	var adapters []serverAdapter
+	for _, a := range serverAdapters {
+		if a.versions(v) {
+			adapters = append(adapters, a)
+		}
+	}
+
+	return adapters, nil
+}
+
+// adaptLegacyList wraps the arrays that list endpoints respond with before
+// Nebraska 2.8.0 in a page
+func adaptLegacyList(req *http.Request, body []byte) ([]byte, error) {
+	key, ok := legacyListKeys[path.Base(req.URL.Path)]
+	body = bytes.TrimSpace(body)
+	if req.Method != http.MethodGet || !ok || len(body) == 0 || body[0] == '{' {
+		return body, nil
+	}
+
+	var items []json.RawMessage
+	if err := json.Unmarshal(body, &items); err != nil {
+		return nil, fmt.Errorf("nebraska: couldn't adapt the list from %s: %w", req.URL.Path, err)
+	}
+	if items == nil {
+		items = []json.RawMessage{}
+	}
+
+	return json.Marshal(map[string]any{
+		key:          items,
+		"count":      len(items),
+		"totalCount": len(items),
+	})
+}
@@ -3,18 +3,19 @@ package nebraska
 import (
 	"fmt"
 	"net/http"
-
-	"github.com/kinvolk/nebraska/backend/pkg/codegen"
+	"strconv"
+	"strings"
+	"time"
 )
 
 // GetGroup retrieves a group by its id
-func (c *Client) GetGroup(appID, id string) (*codegen.Group, error) {
+func (c *Client) GetGroup(appID, id string) (*Group, error) {
 	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s/groups/%s", appID, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Group{}
+	data := &Group{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -23,13 +24,35 @@ func (c *Client) GetGroup(appID, id string) (*codegen.Group, error) {
 }
 
 // ListGroups lists the groups for a particular application
-func (c *Client) ListGroups(appID string) (*codegen.GroupPage, error) {
+func (c *Client) ListGroups(appID string) (*GroupPage, error) {
 	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s/groups?page=1&perpage=10000", appID), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.GroupPage{}
+	data := &GroupPage{}
+	if err := c.do(req, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// GetGroupInstanceStats retrieves a summary of the status of the instances in
+// a group that have checked for updates within the duration, which is one of
+// 1h, 1d, 7d or 30d. Servers without FeatureInstanceStatsDuration ignore the
+// duration and use their default.
+func (c *Client) GetGroupInstanceStats(appID, id, duration string) (*GroupInstanceStats, error) {
+	path := fmt.Sprintf("/api/apps/%s/groups/%s/instances_stats", appID, id)
+	if c.supports(FeatureInstanceStatsDuration) {
+		path += "?duration=" + duration
+	}
+	req, err := c.newRequest(http.MethodGet, path, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &GroupInstanceStats{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -53,13 +76,13 @@ type AddGroupInput struct {
 }
 
 // AddGroup adds a new group
-func (c *Client) AddGroup(appID string, input *AddGroupInput) (*codegen.Group, error) {
+func (c *Client) AddGroup(appID string, input *AddGroupInput) (*Group, error) {
 	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("/api/apps/%s/groups", appID), input)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Group{}
+	data := &Group{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -83,13 +106,13 @@ type UpdateGroupInput struct {
 }
 
 // UpdateGroup updates an existing group
-func (c *Client) UpdateGroup(appID, id string, input *UpdateGroupInput) (*codegen.Group, error) {
+func (c *Client) UpdateGroup(appID, id string, input *UpdateGroupInput) (*Group, error) {
 	req, err := c.newRequest(http.MethodPut, fmt.Sprintf("/api/apps/%s/groups/%s", appID, id), input)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Group{}
+	data := &Group{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -106,3 +129,50 @@ func (c *Client) DeleteGroup(appID, id string) error {
 
 	return c.do(req, nil)
 }
+
+// postgresIntervalUnits are the units of the intervals that Nebraska stores
+// for groups, from largest to smallest
+var postgresIntervalUnits = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"days", 24 * time.Hour},
+	{"hours", time.Hour},
+	{"minutes", time.Minute},
+	{"seconds", time.Second},
+}
+
+// PostgresInterval converts a duration such as 90m, 1h30m or 7d into the
+// interval format that Nebraska uses for policy_period_interval and
+// policy_update_timeout, such as "90 minutes". The largest unit that
+// represents the duration exactly is used.
+func PostgresInterval(s string) (string, error) {
+	var d time.Duration
+	rest := strings.TrimSpace(s)
+	if days, after, ok := strings.Cut(rest, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return "", fmt.Errorf("invalid duration %q", s)
+		}
+		d = time.Duration(n) * 24 * time.Hour
+		rest = after
+	}
+	if rest != "" {
+		t, err := time.ParseDuration(rest)
+		if err != nil {
+			return "", fmt.Errorf("invalid duration %q", s)
+		}
+		d += t
+	}
+	if d <= 0 || d%time.Second != 0 {
+		return "", fmt.Errorf("invalid duration %q: must be a positive number of seconds", s)
+	}
+
+	for _, u := range postgresIntervalUnits {
+		if d%u.duration == 0 {
+			return fmt.Sprintf("%d %s", d/u.duration, u.name), nil
+		}
+	}
+
+	return "", fmt.Errorf("invalid duration %q", s)
+}
@@ -0,0 +1,28 @@
+package nebraska
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestPostgresInterval(t *testing.T) {
+	for in, want := range map[string]string{
+		"1m":    "1 minutes",
+		"90m":   "90 minutes",
+		"1h":    "1 hours",
+		"1h30m": "90 minutes",
+		"7d":    "7 days",
+		"1d12h": "36 hours",
+		"45s":   "45 seconds",
+	} {
+		got, err := PostgresInterval(in)
+		assert.NilError(t, err)
+		assert.Equal(t, got, want, in)
+	}
+
+	for _, in := range []string{"", "0m", "-1h", "1.5s", "10 minutes", "xd"} {
+		_, err := PostgresInterval(in)
+		assert.ErrorContains(t, err, "invalid duration", in)
+	}
+}
@@ -0,0 +1,76 @@
+package nebraska
+
+import (
+	"math"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"golang.org/x/sync/semaphore"
+	"golang.org/x/time/rate"
+)
+
+// Limiter limits the rate and concurrency of the requests of the clients
+// that share it, so that they don't overwhelm a small Nebraska server
+type Limiter struct {
+	rate     *rate.Limiter
+	inFlight *semaphore.Weighted
+
+	// throttled is the total time that requests have waited, in nanoseconds
+	throttled atomic.Int64
+}
+
+// NewLimiter returns a limiter that allows requestsPerSecond requests a
+// second, in bursts of up to a second's worth, and maxConcurrentRequests
+// requests at a time. A limit of 0 disables it.
+func NewLimiter(requestsPerSecond float64, maxConcurrentRequests int) *Limiter {
+	l := &Limiter{}
+	if requestsPerSecond > 0 {
+		l.rate = rate.NewLimiter(rate.Limit(requestsPerSecond), max(1, int(math.Ceil(requestsPerSecond))))
+	}
+	if maxConcurrentRequests > 0 {
+		l.inFlight = semaphore.NewWeighted(int64(maxConcurrentRequests))
+	}
+
+	return l
+}
+
+// Throttled returns the total time that requests have waited for the limiter
+func (l *Limiter) Throttled() time.Duration {
+	return time.Duration(l.throttled.Load())
+}
+
+// wait waits until the request is within the limits, or its context is done,
+// and returns a function that must be called when its response has been read
+func (l *Limiter) wait(req *http.Request) (func(), error) {
+	ctx := req.Context()
+	start := time.Now()
+
+	release := func() {}
+	if l.inFlight != nil {
+		if err := l.inFlight.Acquire(ctx, 1); err != nil {
+			return nil, err
+		}
+		release = func() { l.inFlight.Release(1) }
+	}
+	if l.rate != nil {
+		if err := l.rate.Wait(ctx); err != nil {
+			release()
+			return nil, err
+		}
+	}
+
+	// Waits shorter than a millisecond are just the cost of checking
+	if waited := time.Since(start); waited >= time.Millisecond {
+		total := time.Duration(l.throttled.Add(int64(waited)))
+		tflog.Debug(ctx, "Throttled request to Nebraska", map[string]any{
+			"method":          req.Method,
+			"path":            req.URL.Path,
+			"throttled":       waited.String(),
+			"total_throttled": total.String(),
+		})
+	}
+
+	return release, nil
+}
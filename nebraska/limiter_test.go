@@ -0,0 +1,54 @@
+package nebraska
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+func TestLimiter(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	c, s := testClientServer("", "", "", func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		w.Write([]byte(`{"applications":[],"count":0,"totalCount":0}`))
+	})
+	defer s.Close()
+	l := NewLimiter(0, 2)
+	c.SetLimiter(l)
+
+	var wg sync.WaitGroup
+	for range 6 {
+		wg.Go(func() {
+			_, err := c.ListApplications()
+			assert.NilError(t, err)
+		})
+	}
+	wg.Wait()
+	assert.Equal(t, maxInFlight.Load(), int32(2))
+	assert.Assert(t, l.Throttled() > 0)
+
+	// The burst is used up by the first request, so the second waits for
+	// the next second unless its context is done first
+	c.SetLimiter(NewLimiter(1, 0))
+	_, err := c.ListApplications()
+	assert.NilError(t, err)
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	_, err = c.WithContext(ctx).ListApplications()
+	assert.ErrorContains(t, err, "context deadline")
+	assert.Assert(t, time.Since(start) < 500*time.Millisecond)
+}
@@ -0,0 +1,132 @@
+package nebraska
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+const (
+	// LogSubsystem is the tflog subsystem that the bodies of requests and
+	// responses are logged to, when it's enabled with SetLogBodies
+	LogSubsystem = "http"
+
+	// redacted replaces credentials in the logs
+	redacted = "REDACTED"
+)
+
+// redactedHeaders are the headers that carry credentials
+var redactedHeaders = []string{"Authorization", "Proxy-Authorization", "Cookie", "Set-Cookie"}
+
+// SetLogBodies logs the headers and bodies of the requests and responses of
+// the client to LogSubsystem, with any credentials redacted
+func (c *Client) SetLogBodies(enabled bool) {
+	c.logBodies = enabled
+}
+
+// redact replaces the credentials of the client in s
+func (c *Client) redact(s string) string {
+	var secrets []string
+	for _, secret := range []string{c.password, c.bearerToken} {
+		if secret != "" {
+			secrets = append(secrets, secret, redacted)
+		}
+	}
+	if c.username != "" && c.password != "" {
+		secrets = append(secrets, base64.StdEncoding.EncodeToString([]byte(c.username+":"+c.password)), redacted)
+	}
+	if len(secrets) == 0 {
+		return s
+	}
+
+	return strings.NewReplacer(secrets...).Replace(s)
+}
+
+// redactHeaders returns the headers with the ones that carry credentials
+// redacted
+func redactHeaders(header http.Header) http.Header {
+	header = header.Clone()
+	for _, h := range redactedHeaders {
+		if header.Get(h) != "" {
+			header.Set(h, redacted)
+		}
+	}
+
+	return header
+}
+
+// logRequest logs the headers and body of the request, if the client logs
+// bodies
+func (c *Client) logRequest(req *http.Request) {
+	if !c.logBodies {
+		return
+	}
+
+	var body []byte
+	if req.GetBody != nil {
+		if r, err := req.GetBody(); err == nil {
+			body, _ = io.ReadAll(r)
+			r.Close()
+		}
+	}
+	tflog.SubsystemDebug(req.Context(), LogSubsystem, "Request to Nebraska", map[string]any{
+		"method": req.Method,
+		"url":    c.redact(redactedURL(req)),
+		"header": redactHeaders(req.Header),
+		"body":   c.redact(string(body)),
+	})
+}
+
+// logResponse logs the outcome of the request, and the headers and body of
+// the response if the client logs bodies. The body of the response is read
+// and replaced, so that it can still be decoded.
+func (c *Client) logResponse(req *http.Request, resp *http.Response, err error, duration time.Duration) {
+	ctx := req.Context()
+	fields := map[string]any{
+		"method":   req.Method,
+		"path":     req.URL.Path,
+		"duration": duration.String(),
+		// The client doesn't retry requests, so each is the first attempt
+		"attempt": 1,
+	}
+	if err != nil {
+		fields["error"] = c.redact(err.Error())
+		tflog.Debug(ctx, "Request to Nebraska failed", fields)
+		return
+	}
+	fields["status"] = resp.StatusCode
+	tflog.Debug(ctx, "Sent request to Nebraska", fields)
+
+	if !c.logBodies {
+		return
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), errReader{err}))
+	tflog.SubsystemDebug(ctx, LogSubsystem, "Response from Nebraska", map[string]any{
+		"method": req.Method,
+		"url":    c.redact(redactedURL(req)),
+		"status": resp.StatusCode,
+		"header": redactHeaders(resp.Header),
+		"body":   c.redact(string(body)),
+	})
+}
+
+// errReader returns the error that reading a body failed with, if any, once
+// the part that was read has been
+type errReader struct {
+	err error
+}
+
+func (r errReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+
+	return 0, io.EOF
+}
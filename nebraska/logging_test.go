@@ -0,0 +1,55 @@
+package nebraska
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	"gotest.tools/assert"
+)
+
+func TestClientLogging(t *testing.T) {
+	c, s := testClientServer("user", "secret-password", "", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Set-Cookie", "session=secret-session")
+		w.Write([]byte(`{"id":"ch","application_id":"app","name":"secret-password"}`))
+	})
+	defer s.Close()
+
+	var buf bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &buf)
+	_, err := c.WithContext(ctx).AddChannel("app", &AddChannelInput{Name: "stable"})
+	assert.NilError(t, err)
+	entries, err := tflogtest.MultilineJSONDecode(&buf)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+	entry := entries[1]
+	assert.Equal(t, entry["@message"], "Sent request to Nebraska")
+	assert.Equal(t, entry["method"], http.MethodPost)
+	assert.Equal(t, entry["path"], "/api/apps/app/channels")
+	assert.Equal(t, entry["status"], float64(http.StatusOK))
+	assert.Equal(t, entry["attempt"], float64(1))
+	assert.Assert(t, entry["duration"] != "")
+
+	// Bodies are only logged when they're enabled, with the credentials
+	// redacted
+	buf.Reset()
+	c.SetLogBodies(true)
+	ctx = tflog.NewSubsystem(ctx, LogSubsystem)
+	_, err = c.WithContext(ctx).AddChannel("app", &AddChannelInput{Name: "stable"})
+	assert.NilError(t, err)
+	out := buf.String()
+	assert.Assert(t, !strings.Contains(out, "secret-password"), out)
+	assert.Assert(t, !strings.Contains(out, "secret-session"), out)
+	entries, err = tflogtest.MultilineJSONDecode(&buf)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 3)
+	assert.Equal(t, entries[0]["@message"], "Request to Nebraska")
+	assert.Assert(t, strings.Contains(entries[0]["body"].(string), `"name":"stable"`))
+	assert.DeepEqual(t, entries[0]["header"].(map[string]any)["Authorization"], []any{redacted})
+	assert.Equal(t, entries[2]["@message"], "Response from Nebraska")
+	assert.Equal(t, entries[2]["body"], `{"id":"ch","application_id":"app","name":"REDACTED"}`)
+}
@@ -0,0 +1,145 @@
+package nebraska
+
+import (
+	"time"
+)
+
+// The models of the objects that Nebraska's API returns. They follow the
+// schemas in Nebraska's OpenAPI spec (backend/api/spec.yaml), which
+// TestModels checks them against.
+
+// Application is an application whose updates Nebraska manages, such as
+// Flatcar
+type Application struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	ProductID   string    `json:"product_id"`
+	CreatedTs   time.Time `json:"created_ts"`
+	Channels    []Channel `json:"channels"`
+	Groups      []Group   `json:"groups"`
+	Instances   struct {
+		// Count is the number of instances of the application
+		Count int `json:"count"`
+	} `json:"instances"`
+}
+
+// AppsPage is a page of applications
+type AppsPage struct {
+	Applications []Application `json:"applications"`
+	Count        int           `json:"count"`
+	TotalCount   int           `json:"totalCount"`
+}
+
+// Channel points the groups that provide it at a package for its arch
+type Channel struct {
+	ID            string    `json:"id"`
+	Name          string    `json:"name"`
+	Color         string    `json:"color"`
+	Arch          Arch      `json:"arch"`
+	ApplicationID string    `json:"application_id"`
+	PackageID     string    `json:"package_id"`
+	CreatedTs     time.Time `json:"created_ts"`
+	// Package is the package that the channel points at, if any
+	Package *Package `json:"package,omitempty"`
+}
+
+// ChannelPage is a page of channels
+type ChannelPage struct {
+	Channels   []Channel `json:"channels"`
+	Count      int       `json:"count"`
+	TotalCount int       `json:"totalCount"`
+}
+
+// Group is a group of instances that are updated from a channel according to
+// its update policy
+type Group struct {
+	ID                        string    `json:"id"`
+	Name                      string    `json:"name"`
+	Description               string    `json:"description"`
+	Track                     string    `json:"track"`
+	ApplicationID             string    `json:"application_id"`
+	ChannelID                 string    `json:"channel_id"`
+	CreatedTs                 time.Time `json:"created_ts"`
+	RolloutInProgress         bool      `json:"rollout_in_progress"`
+	PolicyUpdatesEnabled      bool      `json:"policy_updates_enabled"`
+	PolicySafeMode            bool      `json:"policy_safe_mode"`
+	PolicyOfficeHours         bool      `json:"policy_office_hours"`
+	PolicyTimezone            string    `json:"policy_timezone"`
+	PolicyPeriodInterval      string    `json:"policy_period_interval"`
+	PolicyMaxUpdatesPerPeriod int       `json:"policy_max_updates_per_period"`
+	PolicyUpdateTimeout       string    `json:"policy_update_timeout"`
+	// Channel is the channel that the group provides, if any
+	Channel *Channel `json:"channel,omitempty"`
+}
+
+// GroupPage is a page of groups
+type GroupPage struct {
+	Groups     []Group `json:"groups"`
+	Count      int     `json:"count"`
+	TotalCount int     `json:"totalCount"`
+}
+
+// GroupInstanceStats are the number of instances of a group in each update
+// status
+type GroupInstanceStats struct {
+	Total         int `json:"total"`
+	Undefined     int `json:"undefined"`
+	UpdateGranted int `json:"update_granted"`
+	Error         int `json:"error"`
+	Complete      int `json:"complete"`
+	Installed     int `json:"installed"`
+	Downloaded    int `json:"downloaded"`
+	Downloading   int `json:"downloading"`
+	OnHold        int `json:"onHold"`
+}
+
+// Package is a version of an application for an arch
+type Package struct {
+	ID                string         `json:"id"`
+	Type              PackageType    `json:"type"`
+	Version           string         `json:"version"`
+	Arch              Arch           `json:"arch"`
+	URL               string         `json:"url"`
+	Filename          string         `json:"filename"`
+	Description       string         `json:"description"`
+	Size              string         `json:"size"`
+	Hash              string         `json:"hash"`
+	ApplicationID     string         `json:"application_id"`
+	ChannelsBlacklist []string       `json:"channels_blacklist"`
+	CreatedTs         time.Time      `json:"created_ts"`
+	ExtraFiles        []ExtraFile    `json:"extra_files"`
+	FlatcarAction     *FlatcarAction `json:"flatcar_action"`
+}
+
+// ExtraFile is a file that's downloaded along with a package
+type ExtraFile struct {
+	ID      int    `json:"id,omitempty"`
+	Name    string `json:"name,omitempty"`
+	Hash    string `json:"hash,omitempty"`
+	Hash256 string `json:"hash256,omitempty"`
+	Size    string `json:"size,omitempty"`
+}
+
+// PackagePage is a page of packages
+type PackagePage struct {
+	Packages   []Package `json:"packages"`
+	Count      int       `json:"count"`
+	TotalCount int       `json:"totalCount"`
+}
+
+// FlatcarAction is the action that Flatcar instances take to install a
+// package
+type FlatcarAction struct {
+	ID                    string    `json:"id"`
+	Event                 string    `json:"event"`
+	ChromeOSVersion       string    `json:"chromeos_version"`
+	Sha256                string    `json:"sha256"`
+	NeedsAdmin            bool      `json:"needs_admin"`
+	IsDelta               bool      `json:"is_delta"`
+	DisablePayloadBackoff bool      `json:"disable_payload_backoff"`
+	MetadataSignatureRsa  string    `json:"metadata_signature_rsa"`
+	MetadataSize          string    `json:"metadata_size"`
+	Deadline              string    `json:"deadline"`
+	CreatedTs             time.Time `json:"created_ts"`
+}
@@ -0,0 +1,174 @@
+package nebraska
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/assert"
+)
+
+// testOpenAPISchema is a schema in Nebraska's OpenAPI spec
+type testOpenAPISchema struct {
+	Type       string                       `json:"type"`
+	Format     string                       `json:"format"`
+	Ref        string                       `json:"$ref"`
+	Required   []string                     `json:"required"`
+	Properties map[string]testOpenAPISchema `json:"properties"`
+	Items      *testOpenAPISchema           `json:"items"`
+	ExtraTags  struct {
+		JSON string `json:"json"`
+	} `json:"x-oapi-codegen-extra-tags"`
+}
+
+// testOpenAPISchemas returns the schemas in testdata/openapi.json, which is
+// backend/api/spec.yaml from Nebraska converted to JSON, for example with:
+//
+//	yq -o json backend/api/spec.yaml > testdata/openapi.json
+func testOpenAPISchemas(t *testing.T) map[string]testOpenAPISchema {
+	t.Helper()
+
+	data, err := os.ReadFile("testdata/openapi.json")
+	assert.NilError(t, err)
+	var spec struct {
+		Components struct {
+			Schemas map[string]testOpenAPISchema `json:"schemas"`
+		} `json:"components"`
+	}
+	assert.NilError(t, json.Unmarshal(data, &spec))
+
+	return spec.Components.Schemas
+}
+
+// testModels are the types that Nebraska's schemas are decoded into, or
+// encoded from
+var testModels = map[string]reflect.Type{
+	"application":        reflect.TypeFor[Application](),
+	"appsPage":           reflect.TypeFor[AppsPage](),
+	"channel":            reflect.TypeFor[Channel](),
+	"channelPage":        reflect.TypeFor[ChannelPage](),
+	"group":              reflect.TypeFor[Group](),
+	"groupPage":          reflect.TypeFor[GroupPage](),
+	"groupInstanceStats": reflect.TypeFor[GroupInstanceStats](),
+	"package":            reflect.TypeFor[Package](),
+	"packagePage":        reflect.TypeFor[PackagePage](),
+	"flatcarAction":      reflect.TypeFor[FlatcarAction](),
+	"arch":               reflect.TypeFor[Arch](),
+	"extraFiles":         reflect.TypeFor[[]ExtraFile](),
+}
+
+// testJSONFields returns the fields of the struct by their JSON names
+func testJSONFields(typ reflect.Type) map[string]reflect.StructField {
+	fields := map[string]reflect.StructField{}
+	for i := range typ.NumField() {
+		f := typ.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		fields[name] = f
+	}
+
+	return fields
+}
+
+// testJSONName returns the JSON name of the property, which Nebraska sets with
+// an extra tag on the property or the schema it refers to
+func testJSONName(schemas map[string]testOpenAPISchema, name string, property testOpenAPISchema) string {
+	if property.Ref != "" && property.ExtraTags.JSON == "" {
+		property = schemas[strings.TrimPrefix(property.Ref, "#/components/schemas/")]
+	}
+	if property.ExtraTags.JSON != "" {
+		return property.ExtraTags.JSON
+	}
+
+	return name
+}
+
+// testCheckSchema checks that the type has the fields of the schema, with
+// types that they can be decoded into, and no others. Inputs only need the
+// required fields, as Nebraska defaults the rest.
+func testCheckSchema(t *testing.T, schemas map[string]testOpenAPISchema, path string, schema testOpenAPISchema, typ reflect.Type, input bool) {
+	t.Helper()
+
+	if typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	if schema.Ref != "" {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		if model, ok := testModels[name]; ok && !input {
+			assert.Equal(t, typ, model, path)
+			return
+		}
+		schema = schemas[name]
+	}
+
+	switch schema.Type {
+	case "string":
+		if schema.Format == "date-time" {
+			assert.Equal(t, typ, reflect.TypeFor[time.Time](), path)
+			return
+		}
+		assert.Equal(t, typ.Kind(), reflect.String, path)
+	case "integer":
+		assert.Assert(t, typ.Kind() >= reflect.Int && typ.Kind() <= reflect.Uint64, "%s: got %s, want an integer", path, typ)
+	case "boolean":
+		assert.Equal(t, typ.Kind(), reflect.Bool, path)
+	case "array":
+		assert.Equal(t, typ.Kind(), reflect.Slice, path)
+		testCheckSchema(t, schemas, path+"[]", *schema.Items, typ.Elem(), input)
+	case "object":
+		assert.Equal(t, typ.Kind(), reflect.Struct, path)
+		fields := testJSONFields(typ)
+		properties := map[string]testOpenAPISchema{}
+		for name, property := range schema.Properties {
+			properties[testJSONName(schemas, name, property)] = property
+		}
+		for name, property := range properties {
+			f, ok := fields[name]
+			if !ok {
+				if !input {
+					t.Errorf("%s: missing %s", path, name)
+				}
+				continue
+			}
+			testCheckSchema(t, schemas, path+"."+name, property, f.Type, input)
+		}
+		for name := range fields {
+			_, ok := properties[name]
+			assert.Assert(t, ok, "%s: %s isn't in the spec", path, name)
+		}
+		if input {
+			for _, name := range schema.Required {
+				name = testJSONName(schemas, name, schema.Properties[name])
+				_, ok := fields[name]
+				assert.Assert(t, ok, "%s: missing required %s", path, name)
+			}
+		}
+	default:
+		t.Errorf("%s: unsupported schema type %q", path, schema.Type)
+	}
+}
+
+func TestModels(t *testing.T) {
+	schemas := testOpenAPISchemas(t)
+
+	for name, model := range testModels {
+		schema, ok := schemas[name]
+		assert.Assert(t, ok, "%s isn't in the spec", name)
+		if name == "arch" {
+			assert.Equal(t, schema.Type, "integer")
+			continue
+		}
+		testCheckSchema(t, schemas, name, schema, model, false)
+	}
+
+	for name, input := range map[string]any{
+		"appConfig":     AddApplicationInput{},
+		"channelConfig": AddChannelInput{},
+		"groupConfig":   AddGroupInput{},
+		"packageConfig": AddPackageInput{},
+	} {
+		testCheckSchema(t, schemas, name, schemas[name], reflect.TypeOf(input), true)
+	}
+}
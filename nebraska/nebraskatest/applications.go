@@ -0,0 +1,87 @@
+package nebraskatest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func (s *Server) listApplications(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Nebraska returns the newest applications first
+	apps := slices.Clone(s.apps)
+	slices.Reverse(apps)
+	page, err := paginate(r, apps)
+	if err != nil {
+		reject(w, err)
+		return
+	}
+
+	data := nebraska.AppsPage{
+		Applications: []nebraska.Application{},
+		Count:        len(page),
+		TotalCount:   len(apps),
+	}
+	for _, app := range page {
+		data.Applications = append(data.Applications, s.applicationResponse(app))
+	}
+	s.respondPage(w, data, data.Applications)
+}
+
+func (s *Server) addApplication(w http.ResponseWriter, r *http.Request) {
+	var config appConfig
+	if !decode(w, r, &config) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := &nebraska.Application{
+		ID:          newID(),
+		Name:        config.Name,
+		Description: deref(config.Description),
+		ProductID:   deref(config.ProductID),
+		CreatedTs:   s.now(),
+	}
+	for _, a := range s.apps {
+		if a.Name == app.Name || (app.ProductID != "" && a.ProductID == app.ProductID) {
+			reject(w, errConflict)
+			return
+		}
+	}
+	s.apps = append(s.apps, app)
+
+	respond(w, s.applicationResponse(app))
+}
+
+func (s *Server) getApplication(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	respond(w, s.applicationResponse(app))
+}
+
+// applicationResponse returns the application with its channels and groups,
+// as Nebraska returns it
+func (s *Server) applicationResponse(app *nebraska.Application) nebraska.Application {
+	data := *app
+	data.Channels = []nebraska.Channel{}
+	for _, ch := range s.appChannels(app.ID) {
+		data.Channels = append(data.Channels, s.channelResponse(ch))
+	}
+	data.Groups = []nebraska.Group{}
+	for _, g := range s.appGroups(app.ID) {
+		data.Groups = append(data.Groups, s.groupResponse(g))
+	}
+
+	return data
+}
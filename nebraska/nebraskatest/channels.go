@@ -0,0 +1,210 @@
+package nebraskatest
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func (s *Server) listChannels(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	channels := s.appChannels(app.ID)
+	page, err := paginate(r, channels)
+	if err != nil {
+		reject(w, err)
+		return
+	}
+
+	data := nebraska.ChannelPage{
+		Channels:   []nebraska.Channel{},
+		Count:      len(page),
+		TotalCount: len(channels),
+	}
+	for _, ch := range page {
+		data.Channels = append(data.Channels, s.channelResponse(ch))
+	}
+	s.respondPage(w, data, data.Channels)
+}
+
+func (s *Server) addChannel(w http.ResponseWriter, r *http.Request) {
+	var config channelConfig
+	if !decode(w, r, &config) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	ch := &nebraska.Channel{
+		ID:        newID(),
+		CreatedTs: s.now(),
+	}
+	if err := s.setChannel(app.ID, ch, config); err != nil {
+		reject(w, err)
+		return
+	}
+	s.channels = append(s.channels, ch)
+
+	respond(w, s.channelResponse(ch))
+}
+
+func (s *Server) getChannel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := s.channel(w, r)
+	if ch == nil {
+		return
+	}
+
+	respond(w, s.channelResponse(ch))
+}
+
+func (s *Server) updateChannel(w http.ResponseWriter, r *http.Request) {
+	var config channelConfig
+	if !decode(w, r, &config) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := s.channel(w, r)
+	if ch == nil {
+		return
+	}
+
+	updated := *ch
+	if err := s.setChannel(ch.ApplicationID, &updated, config); err != nil {
+		reject(w, err)
+		return
+	}
+	*ch = updated
+
+	respond(w, s.channelResponse(ch))
+}
+
+func (s *Server) deleteChannel(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ch := s.channel(w, r)
+	if ch == nil {
+		return
+	}
+
+	s.channels = slices.DeleteFunc(s.channels, func(c *nebraska.Channel) bool { return c == ch })
+	for _, g := range s.groups {
+		if g.ChannelID == ch.ID {
+			g.ChannelID = ""
+		}
+	}
+	for _, p := range s.packages {
+		p.ChannelsBlacklist = slices.DeleteFunc(p.ChannelsBlacklist, func(id string) bool { return id == ch.ID })
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// channel returns the channel in the path, or responds with a 404
+func (s *Server) channel(w http.ResponseWriter, r *http.Request) *nebraska.Channel {
+	app := s.application(w, r)
+	if app == nil {
+		return nil
+	}
+	id := r.PathValue("id")
+	for _, ch := range s.channels {
+		if ch.ID == id && ch.ApplicationID == app.ID {
+			return ch
+		}
+	}
+	http.NotFound(w, r)
+
+	return nil
+}
+
+// appChannels returns the channels of the application, by name like Nebraska
+func (s *Server) appChannels(appID string) []*nebraska.Channel {
+	var channels []*nebraska.Channel
+	for _, ch := range s.channels {
+		if ch.ApplicationID == appID {
+			channels = append(channels, ch)
+		}
+	}
+	slices.SortStableFunc(channels, func(a, b *nebraska.Channel) int { return strings.Compare(a.Name, b.Name) })
+
+	return channels
+}
+
+// setChannel sets the channel to the configuration if it doesn't break any of
+// the rules for channels
+func (s *Server) setChannel(appID string, ch *nebraska.Channel, config channelConfig) error {
+	arch := nebraska.Arch(config.Arch)
+	if !arch.IsValid() {
+		return errInvalidArch
+	}
+	for _, c := range s.channels {
+		if c.ID != ch.ID && c.ApplicationID == appID && c.Name == config.Name && c.Arch == arch {
+			return errConflict
+		}
+	}
+
+	packageID := deref(config.PackageID)
+	if packageID != "" {
+		pkg := s.findPackage(packageID)
+		if pkg == nil || pkg.ApplicationID != appID {
+			return errInvalidPackage
+		}
+		if pkg.Arch != arch {
+			return errArchMismatch
+		}
+		if slices.Contains(pkg.ChannelsBlacklist, ch.ID) {
+			return errBlacklistedChannel
+		}
+	}
+
+	ch.ApplicationID = appID
+	ch.Name = config.Name
+	ch.Color = config.Color
+	ch.Arch = arch
+	ch.PackageID = packageID
+
+	return nil
+}
+
+// findChannel returns the channel with the id in any application
+func (s *Server) findChannel(id string) *nebraska.Channel {
+	for _, ch := range s.channels {
+		if ch.ID == id {
+			return ch
+		}
+	}
+
+	return nil
+}
+
+// channelResponse returns the channel with its package, as Nebraska returns
+// it
+func (s *Server) channelResponse(ch *nebraska.Channel) nebraska.Channel {
+	data := *ch
+	if pkg := s.findPackage(ch.PackageID); pkg != nil {
+		p := s.packageResponse(pkg)
+		data.Package = &p
+	}
+
+	return data
+}
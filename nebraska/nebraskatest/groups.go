@@ -0,0 +1,204 @@
+package nebraskatest
+
+import (
+	"net/http"
+	"slices"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+func (s *Server) listGroups(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	groups := s.appGroups(app.ID)
+	page, err := paginate(r, groups)
+	if err != nil {
+		reject(w, err)
+		return
+	}
+
+	data := nebraska.GroupPage{
+		Groups:     []nebraska.Group{},
+		Count:      len(page),
+		TotalCount: len(groups),
+	}
+	for _, g := range page {
+		data.Groups = append(data.Groups, s.groupResponse(g))
+	}
+	s.respondPage(w, data, data.Groups)
+}
+
+func (s *Server) addGroup(w http.ResponseWriter, r *http.Request) {
+	var config groupConfig
+	if !decode(w, r, &config) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	g := &nebraska.Group{
+		ID:        newID(),
+		CreatedTs: s.now(),
+	}
+	if err := s.setGroup(app.ID, g, config); err != nil {
+		reject(w, err)
+		return
+	}
+	s.groups = append(s.groups, g)
+
+	respond(w, s.groupResponse(g))
+}
+
+func (s *Server) getGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.group(w, r)
+	if g == nil {
+		return
+	}
+
+	respond(w, s.groupResponse(g))
+}
+
+func (s *Server) updateGroup(w http.ResponseWriter, r *http.Request) {
+	var config groupConfig
+	if !decode(w, r, &config) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.group(w, r)
+	if g == nil {
+		return
+	}
+
+	updated := *g
+	if err := s.setGroup(g.ApplicationID, &updated, config); err != nil {
+		reject(w, err)
+		return
+	}
+	*g = updated
+
+	respond(w, s.groupResponse(g))
+}
+
+func (s *Server) deleteGroup(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.group(w, r)
+	if g == nil {
+		return
+	}
+
+	s.groups = slices.DeleteFunc(s.groups, func(group *nebraska.Group) bool { return group == g })
+	delete(s.stats, g.ID)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (s *Server) getGroupInstanceStats(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	g := s.group(w, r)
+	if g == nil {
+		return
+	}
+
+	respond(w, s.stats[g.ID])
+}
+
+// group returns the group in the path, or responds with a 404
+func (s *Server) group(w http.ResponseWriter, r *http.Request) *nebraska.Group {
+	app := s.application(w, r)
+	if app == nil {
+		return nil
+	}
+	id := r.PathValue("id")
+	for _, g := range s.groups {
+		if g.ID == id && g.ApplicationID == app.ID {
+			return g
+		}
+	}
+	http.NotFound(w, r)
+
+	return nil
+}
+
+// appGroups returns the groups of the application, newest first like
+// Nebraska
+func (s *Server) appGroups(appID string) []*nebraska.Group {
+	var groups []*nebraska.Group
+	for _, g := range s.groups {
+		if g.ApplicationID == appID {
+			groups = append(groups, g)
+		}
+	}
+	slices.Reverse(groups)
+
+	return groups
+}
+
+// setGroup sets the group to the configuration if it doesn't break any of the
+// rules for groups
+func (s *Server) setGroup(appID string, g *nebraska.Group, config groupConfig) error {
+	for _, group := range s.groups {
+		if group.ID != g.ID && group.ApplicationID == appID && group.Name == config.Name {
+			return errConflict
+		}
+	}
+
+	channelID := deref(config.ChannelID)
+	if channelID != "" {
+		ch := s.findChannel(channelID)
+		if ch == nil || ch.ApplicationID != appID {
+			return errInvalidChannel
+		}
+	}
+
+	g.ApplicationID = appID
+	g.Name = config.Name
+	g.Description = deref(config.Description)
+	g.ChannelID = channelID
+	g.PolicyUpdatesEnabled = deref(config.PolicyUpdatesEnabled)
+	g.PolicySafeMode = deref(config.PolicySafeMode)
+	g.PolicyOfficeHours = deref(config.PolicyOfficeHours)
+	g.PolicyTimezone = config.PolicyTimezone
+	g.PolicyPeriodInterval = config.PolicyPeriodInterval
+	g.PolicyMaxUpdatesPerPeriod = config.PolicyMaxUpdatesPerPeriod
+	g.PolicyUpdateTimeout = config.PolicyUpdateTimeout
+	// Nebraska uses the id of the group when it doesn't have a track
+	g.Track = deref(config.Track)
+	if g.Track == "" {
+		g.Track = g.ID
+	}
+
+	return nil
+}
+
+// groupResponse returns the group with its channel, as Nebraska returns it
+func (s *Server) groupResponse(g *nebraska.Group) nebraska.Group {
+	data := *g
+	if ch := s.findChannel(g.ChannelID); ch != nil {
+		c := s.channelResponse(ch)
+		data.Channel = &c
+	}
+
+	return data
+}
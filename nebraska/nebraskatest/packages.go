@@ -0,0 +1,264 @@
+package nebraskatest
+
+import (
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/blang/semver/v4"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// versionNumbers matches the numbers that Nebraska orders packages by
+var versionNumbers = regexp.MustCompile(`(\d+)\.(\d+)\.(\d+)`)
+
+func (s *Server) listPackages(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	packages := s.appPackages(app.ID)
+	// Nebraska ignores the search before 2.8.0
+	if search := r.URL.Query().Get("searchVersion"); search != "" && !s.olderThan("2.8.0") {
+		packages = slices.DeleteFunc(packages, func(p *nebraska.Package) bool {
+			return !strings.Contains(strings.ToLower(p.Version), strings.ToLower(search))
+		})
+	}
+	page, err := paginate(r, packages)
+	if err != nil {
+		reject(w, err)
+		return
+	}
+
+	data := nebraska.PackagePage{
+		Packages:   []nebraska.Package{},
+		Count:      len(page),
+		TotalCount: len(packages),
+	}
+	for _, p := range page {
+		data.Packages = append(data.Packages, s.packageResponse(p))
+	}
+	s.respondPage(w, data, data.Packages)
+}
+
+func (s *Server) addPackage(w http.ResponseWriter, r *http.Request) {
+	var config packageConfig
+	if !decode(w, r, &config) {
+		return
+	}
+	// A missing or null blacklist is decoded as nil, an empty one isn't
+	if config.ChannelsBlacklist == nil {
+		reject(w, errNullBlacklist)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	app := s.application(w, r)
+	if app == nil {
+		return
+	}
+
+	pkg := &nebraska.Package{
+		ID:        newID(),
+		CreatedTs: s.now(),
+	}
+	if err := s.setPackage(app.ID, pkg, config); err != nil {
+		reject(w, err)
+		return
+	}
+	s.packages = append(s.packages, pkg)
+
+	respond(w, s.packageResponse(pkg))
+}
+
+func (s *Server) getPackage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pkg := s.pkg(w, r)
+	if pkg == nil {
+		return
+	}
+
+	respond(w, s.packageResponse(pkg))
+}
+
+func (s *Server) updatePackage(w http.ResponseWriter, r *http.Request) {
+	var config packageConfig
+	if !decode(w, r, &config) {
+		return
+	}
+	// A missing or null blacklist is decoded as nil, an empty one isn't
+	if config.ChannelsBlacklist == nil {
+		reject(w, errNullBlacklist)
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pkg := s.pkg(w, r)
+	if pkg == nil {
+		return
+	}
+
+	updated := *pkg
+	if err := s.setPackage(pkg.ApplicationID, &updated, config); err != nil {
+		reject(w, err)
+		return
+	}
+	*pkg = updated
+
+	respond(w, s.packageResponse(pkg))
+}
+
+func (s *Server) deletePackage(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pkg := s.pkg(w, r)
+	if pkg == nil {
+		return
+	}
+
+	s.packages = slices.DeleteFunc(s.packages, func(p *nebraska.Package) bool { return p == pkg })
+	for _, ch := range s.channels {
+		if ch.PackageID == pkg.ID {
+			ch.PackageID = ""
+		}
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pkg returns the package in the path, or responds with a 404
+func (s *Server) pkg(w http.ResponseWriter, r *http.Request) *nebraska.Package {
+	app := s.application(w, r)
+	if app == nil {
+		return nil
+	}
+	id := r.PathValue("id")
+	for _, p := range s.packages {
+		if p.ID == id && p.ApplicationID == app.ID {
+			return p
+		}
+	}
+	http.NotFound(w, r)
+
+	return nil
+}
+
+// appPackages returns the packages of the application, newest version first
+// like Nebraska
+func (s *Server) appPackages(appID string) []*nebraska.Package {
+	var packages []*nebraska.Package
+	for _, p := range s.packages {
+		if p.ApplicationID == appID {
+			packages = append(packages, p)
+		}
+	}
+	slices.SortStableFunc(packages, func(a, b *nebraska.Package) int {
+		return slices.Compare(versionKey(b.Version), versionKey(a.Version))
+	})
+
+	return packages
+}
+
+// versionKey returns the numbers of the version that Nebraska orders packages
+// by
+func versionKey(version string) []int {
+	var key []int
+	if m := versionNumbers.FindStringSubmatch(version); m != nil {
+		for _, n := range m[1:] {
+			i, _ := strconv.Atoi(n)
+			key = append(key, i)
+		}
+	}
+
+	return key
+}
+
+// setPackage sets the package to the configuration if it doesn't break any
+// of the rules for packages
+func (s *Server) setPackage(appID string, pkg *nebraska.Package, config packageConfig) error {
+	if _, err := semver.Make(config.Version); err != nil {
+		return errInvalidSemver
+	}
+	arch := nebraska.Arch(config.Arch)
+	if !arch.IsValid() {
+		return errInvalidArch
+	}
+	for _, p := range s.packages {
+		if p.ID != pkg.ID && p.ApplicationID == appID && p.Version == config.Version && p.Arch == arch {
+			return errConflict
+		}
+	}
+
+	for _, channelID := range config.ChannelsBlacklist {
+		ch := s.findChannel(channelID)
+		if ch == nil {
+			continue
+		}
+		if ch.Arch != arch {
+			return errArchMismatch
+		}
+		if ch.PackageID == pkg.ID && !slices.Contains(pkg.ChannelsBlacklist, channelID) {
+			return errBlacklistingChannel
+		}
+	}
+
+	pkg.ApplicationID = appID
+	pkg.Arch = arch
+	pkg.ChannelsBlacklist = slices.Clone(config.ChannelsBlacklist)
+	pkg.Description = config.Description
+	pkg.ExtraFiles = config.ExtraFiles
+	pkg.Filename = config.Filename
+	pkg.Hash = config.Hash
+	pkg.Size = config.Size
+	pkg.Type = nebraska.PackageType(config.Type)
+	pkg.URL = config.URL
+	pkg.Version = config.Version
+
+	pkg.FlatcarAction = nil
+	if config.FlatcarAction != nil {
+		// The defaults of the flatcar_action table
+		pkg.FlatcarAction = &nebraska.FlatcarAction{
+			ID:                    newID(),
+			Event:                 "postinstall",
+			DisablePayloadBackoff: true,
+			Sha256:                deref(config.FlatcarAction.Sha256),
+			CreatedTs:             s.now(),
+		}
+	}
+
+	return nil
+}
+
+// findPackage returns the package with the id in any application
+func (s *Server) findPackage(id string) *nebraska.Package {
+	for _, p := range s.packages {
+		if p.ID == id {
+			return p
+		}
+	}
+
+	return nil
+}
+
+// packageResponse returns the package as Nebraska returns it
+func (s *Server) packageResponse(pkg *nebraska.Package) nebraska.Package {
+	data := *pkg
+	if data.ChannelsBlacklist == nil {
+		data.ChannelsBlacklist = []string{}
+	}
+
+	return data
+}
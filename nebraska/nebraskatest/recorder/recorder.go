@@ -0,0 +1,303 @@
+// Package recorder records the requests that a client makes to a Nebraska
+// server and their responses into fixture files, and replays them in tests
+// that run without a server.
+//
+// It doesn't depend on the nebraska package, so that package's own tests can
+// use it.
+package recorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+)
+
+// Mode is whether tests run against a Nebraska server, record the requests
+// they make to it or replay recorded requests
+type Mode string
+
+const (
+	// ModeLive runs against the Nebraska server without recording
+	ModeLive Mode = "live"
+	// ModeRecord runs against the Nebraska server and records the requests
+	// into fixtures
+	ModeRecord Mode = "record"
+	// ModeReplay replays the fixtures without a Nebraska server
+	ModeReplay Mode = "replay"
+)
+
+const (
+	// redacted replaces the values of the scrubbed headers
+	redacted = "REDACTED"
+)
+
+// scrubbedHeaders are the request headers that are redacted, because they
+// carry credentials
+var scrubbedHeaders = []string{"Authorization", "Cookie"}
+
+// droppedHeaders are the request headers that aren't recorded, because they
+// change with every run
+var droppedHeaders = []string{"Traceparent", "Tracestate"}
+
+// ModeFromEnv returns the mode set by NEBRASKA_TEST_MODE, which defaults to
+// live when NEBRASKA_ENDPOINT is set and replay otherwise
+func ModeFromEnv() (Mode, error) {
+	switch mode := Mode(os.Getenv("NEBRASKA_TEST_MODE")); mode {
+	case ModeLive, ModeRecord, ModeReplay:
+		return mode, nil
+	case "":
+		if os.Getenv("NEBRASKA_ENDPOINT") != "" {
+			return ModeLive, nil
+		}
+		return ModeReplay, nil
+	default:
+		return "", fmt.Errorf("invalid NEBRASKA_TEST_MODE %q: must be one of live, record or replay", mode)
+	}
+}
+
+// Fixture is the requests recorded from a test, in the order they were made
+type Fixture struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Interaction is a request and its response
+type Interaction struct {
+	Request  Request  `json:"request"`
+	Response Response `json:"response"`
+}
+
+// Request is a recorded request. The URL is the path and query only, so that
+// it doesn't depend on the address of the server.
+type Request struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body,omitempty"`
+}
+
+// Response is a recorded response
+type Response struct {
+	StatusCode int         `json:"status_code"`
+	Header     http.Header `json:"header,omitempty"`
+	Body       string      `json:"body,omitempty"`
+}
+
+// Recorder is an http.RoundTripper that records requests and their responses
+// in record mode, and responds to requests with the recorded responses in
+// replay mode
+type Recorder struct {
+	mode      Mode
+	path      string
+	transport http.RoundTripper
+
+	mu       sync.Mutex
+	fixture  Fixture
+	replayed []bool
+}
+
+// New returns a recorder for the fixture at the path. In record mode the
+// requests are sent with the transport. In replay mode the fixture is loaded,
+// and the error wraps fs.ErrNotExist if it hasn't been recorded.
+func New(path string, mode Mode, transport http.RoundTripper) (*Recorder, error) {
+	r := &Recorder{
+		mode:      mode,
+		path:      path,
+		transport: transport,
+	}
+
+	switch mode {
+	case ModeRecord:
+	case ModeReplay:
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("couldn't read fixture: %w", err)
+		}
+		if err := json.Unmarshal(data, &r.fixture); err != nil {
+			return nil, fmt.Errorf("couldn't parse fixture %s: %w", path, err)
+		}
+		r.replayed = make([]bool, len(r.fixture.Interactions))
+	default:
+		return nil, fmt.Errorf("recorder: unsupported mode %q", mode)
+	}
+
+	return r, nil
+}
+
+// RoundTrip records or replays the request
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+	recorded := Request{
+		Method: req.Method,
+		URL:    req.URL.RequestURI(),
+		Header: scrub(req.Header),
+		Body:   string(body),
+	}
+
+	if r.mode == ModeReplay {
+		return r.replay(req, recorded)
+	}
+
+	resp, err := r.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(respBody))
+
+	header := resp.Header.Clone()
+	header.Del("Date")
+	r.mu.Lock()
+	r.fixture.Interactions = append(r.fixture.Interactions, Interaction{
+		Request: recorded,
+		Response: Response{
+			StatusCode: resp.StatusCode,
+			Header:     header,
+			Body:       string(respBody),
+		},
+	})
+	r.mu.Unlock()
+
+	return resp, nil
+}
+
+// replay responds with the first recorded response to a matching request that
+// hasn't been replayed yet. Requests match if they have the same method and
+// URL and equivalent bodies, regardless of their headers.
+func (r *Recorder) replay(req *http.Request, recorded Request) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i, interaction := range r.fixture.Interactions {
+		if r.replayed[i] || !interaction.Request.matches(recorded) {
+			continue
+		}
+		r.replayed[i] = true
+
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+			StatusCode:    interaction.Response.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        interaction.Response.Header.Clone(),
+			Body:          io.NopCloser(bytes.NewBufferString(interaction.Response.Body)),
+			ContentLength: int64(len(interaction.Response.Body)),
+			Request:       req,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("recorder: no recorded response to %s %s in %s", recorded.Method, recorded.URL, r.path)
+}
+
+// Close writes the fixture in record mode. In replay mode it returns an error
+// if any of the recorded requests weren't made.
+func (r *Recorder) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.mode == ModeReplay {
+		var missing []error
+		for i, interaction := range r.fixture.Interactions {
+			if !r.replayed[i] {
+				missing = append(missing, fmt.Errorf("recorder: %s %s in %s wasn't replayed", interaction.Request.Method, interaction.Request.URL, r.path))
+			}
+		}
+		return errors.Join(missing...)
+	}
+
+	data, err := json.MarshalIndent(r.fixture, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+
+	return os.WriteFile(r.path, append(data, '\n'), 0o644)
+}
+
+// Server starts a server that forwards requests to the Nebraska server at the
+// target URL through the recorder, for clients that can't be given a
+// transport, such as a provider run by Terraform. The target isn't used in
+// replay mode.
+func (r *Recorder) Server(target string) (*httptest.Server, error) {
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, err
+	}
+	if r.mode == ModeReplay {
+		u = &url.URL{Scheme: "http", Host: "nebraska.invalid"}
+	}
+
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(u)
+		},
+		Transport: r,
+	}
+
+	return httptest.NewServer(proxy), nil
+}
+
+// matches returns true if the requests have the same method and URL and
+// equivalent bodies
+func (req Request) matches(other Request) bool {
+	if req.Method != other.Method || req.URL != other.URL {
+		return false
+	}
+	if req.Body == other.Body {
+		return true
+	}
+
+	var a, b any
+	if json.Unmarshal([]byte(req.Body), &a) != nil || json.Unmarshal([]byte(other.Body), &b) != nil {
+		return false
+	}
+
+	return reflect.DeepEqual(a, b)
+}
+
+// scrub returns the headers with the ones that carry credentials redacted,
+// and without the ones that change with every run
+func scrub(header http.Header) http.Header {
+	if len(header) == 0 {
+		return nil
+	}
+	header = header.Clone()
+	for _, h := range scrubbedHeaders {
+		if header.Get(h) != "" {
+			header.Set(h, redacted)
+		}
+	}
+	for _, h := range droppedHeaders {
+		header.Del(h)
+	}
+	if len(header) == 0 {
+		return nil
+	}
+
+	return header
+}
@@ -0,0 +1,137 @@
+package recorder
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func testGet(t *testing.T, c *http.Client, url string) (int, string) {
+	t.Helper()
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	assert.NilError(t, err)
+	req.Header.Set("Authorization", "Bearer secret")
+	resp, err := c.Do(req)
+	assert.NilError(t, err)
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	assert.NilError(t, err)
+
+	return resp.StatusCode, string(body)
+}
+
+func TestRecorder(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixtures", "TestRecorder.json")
+	calls := 0
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if r.URL.Path != "/api/apps" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		io.WriteString(w, `{"count":1}`)
+	}))
+	defer s.Close()
+
+	rec, err := New(path, ModeRecord, http.DefaultTransport)
+	assert.NilError(t, err)
+	c := &http.Client{Transport: rec}
+	status, body := testGet(t, c, s.URL+"/api/apps?page=1")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, `{"count":1}`)
+	status, _ = testGet(t, c, s.URL+"/api/apps/missing")
+	assert.Equal(t, status, http.StatusNotFound)
+	assert.NilError(t, rec.Close())
+
+	fixture, err := os.ReadFile(path)
+	assert.NilError(t, err)
+	assert.Assert(t, !strings.Contains(string(fixture), "secret"))
+	assert.Assert(t, strings.Contains(string(fixture), `"REDACTED"`))
+
+	// Replay doesn't reach the server
+	s.Close()
+	rec, err = New(path, ModeReplay, nil)
+	assert.NilError(t, err)
+	c = &http.Client{Transport: rec}
+	status, _ = testGet(t, c, "http://nebraska.invalid/api/apps/missing")
+	assert.Equal(t, status, http.StatusNotFound)
+	assert.ErrorContains(t, rec.Close(), "GET /api/apps?page=1 in "+path+" wasn't replayed")
+	status, body = testGet(t, c, "http://nebraska.invalid/api/apps?page=1")
+	assert.Equal(t, status, http.StatusOK)
+	assert.Equal(t, body, `{"count":1}`)
+	assert.NilError(t, rec.Close())
+	assert.Equal(t, calls, 2)
+
+	// Each recorded response is only replayed once
+	_, err = c.Get("http://nebraska.invalid/api/apps?page=1")
+	assert.ErrorContains(t, err, "no recorded response to GET /api/apps?page=1")
+
+	_, err = New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay, nil)
+	assert.Assert(t, errors.Is(err, fs.ErrNotExist))
+}
+
+func TestRecorderServer(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "TestRecorderServer.json")
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer s.Close()
+
+	rec, err := New(path, ModeRecord, http.DefaultTransport)
+	assert.NilError(t, err)
+	proxy, err := rec.Server(s.URL)
+	assert.NilError(t, err)
+	resp, err := http.Post(proxy.URL+"/api/apps", "application/json", strings.NewReader(`{"name":"a","description":"b"}`))
+	assert.NilError(t, err)
+	resp.Body.Close()
+	proxy.Close()
+	assert.NilError(t, rec.Close())
+
+	// Bodies match regardless of the order of their keys
+	rec, err = New(path, ModeReplay, nil)
+	assert.NilError(t, err)
+	proxy, err = rec.Server("")
+	assert.NilError(t, err)
+	defer proxy.Close()
+	resp, err = http.Post(proxy.URL+"/api/apps", "application/json", strings.NewReader(`{"description":"b","name":"a"}`))
+	assert.NilError(t, err)
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	assert.NilError(t, err)
+	assert.Equal(t, resp.StatusCode, http.StatusOK)
+	assert.Equal(t, string(body), `{"name":"a","description":"b"}`)
+	assert.NilError(t, rec.Close())
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv("NEBRASKA_TEST_MODE", "")
+	t.Setenv("NEBRASKA_ENDPOINT", "")
+	mode, err := ModeFromEnv()
+	assert.NilError(t, err)
+	assert.Equal(t, mode, ModeReplay)
+
+	t.Setenv("NEBRASKA_ENDPOINT", "http://localhost:8000")
+	mode, err = ModeFromEnv()
+	assert.NilError(t, err)
+	assert.Equal(t, mode, ModeLive)
+
+	t.Setenv("NEBRASKA_TEST_MODE", "record")
+	mode, err = ModeFromEnv()
+	assert.NilError(t, err)
+	assert.Equal(t, mode, ModeRecord)
+
+	t.Setenv("NEBRASKA_TEST_MODE", "rewind")
+	_, err = ModeFromEnv()
+	assert.ErrorContains(t, err, "invalid NEBRASKA_TEST_MODE")
+}
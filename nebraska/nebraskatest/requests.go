@@ -0,0 +1,59 @@
+package nebraskatest
+
+import (
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+// The bodies of the requests that add and update objects, as Nebraska
+// decodes them from the appConfig, channelConfig, groupConfig and
+// packageConfig schemas of its OpenAPI spec. They're separate from the
+// client's input types so that the fake doesn't accept whatever the client
+// sends.
+
+type appConfig struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
+	ProductID   *string `json:"product_id"`
+}
+
+type channelConfig struct {
+	ApplicationID string  `json:"application_id"`
+	Arch          uint    `json:"arch"`
+	Color         string  `json:"color"`
+	Name          string  `json:"name"`
+	PackageID     *string `json:"package_id,omitempty"`
+}
+
+type groupConfig struct {
+	ChannelID                 *string `json:"channel_id,omitempty"`
+	Description               *string `json:"description,omitempty"`
+	Name                      string  `json:"name"`
+	PolicyMaxUpdatesPerPeriod int     `json:"policy_max_updates_per_period"`
+	PolicyOfficeHours         *bool   `json:"policy_office_hours,omitempty"`
+	PolicyPeriodInterval      string  `json:"policy_period_interval"`
+	PolicySafeMode            *bool   `json:"policy_safe_mode,omitempty"`
+	PolicyTimezone            string  `json:"policy_timezone"`
+	PolicyUpdateTimeout       string  `json:"policy_update_timeout"`
+	PolicyUpdatesEnabled      *bool   `json:"policy_updates_enabled,omitempty"`
+	Track                     *string `json:"track,omitempty"`
+}
+
+type packageConfig struct {
+	ApplicationID     string                `json:"application_id"`
+	Arch              uint                  `json:"arch"`
+	ChannelsBlacklist []string              `json:"channels_blacklist"`
+	Description       string                `json:"description"`
+	ExtraFiles        []nebraska.ExtraFile  `json:"extra_files"`
+	Filename          string                `json:"filename"`
+	FlatcarAction     *flatcarActionPackage `json:"flatcar_action"`
+	Hash              string                `json:"hash"`
+	Size              string                `json:"size"`
+	Type              int                   `json:"type"`
+	URL               string                `json:"url"`
+	Version           string                `json:"version"`
+}
+
+type flatcarActionPackage struct {
+	ID     *string `json:"id,omitempty"`
+	Sha256 *string `json:"sha256,omitempty"`
+}
@@ -0,0 +1,280 @@
+// Package nebraskatest provides an in-memory fake of the Nebraska API, for
+// testing code built on nebraska.Client without Postgres or the Nebraska
+// image.
+package nebraskatest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/google/uuid"
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+)
+
+const (
+	// defaultPage and defaultPerPage are the pagination that Nebraska uses
+	// when a list request doesn't ask for any
+	defaultPage    = 1
+	defaultPerPage = 10
+
+	// DefaultVersion is the version of Nebraska that the server reports,
+	// unless it's changed with SetVersion
+	DefaultVersion = "2.9.0"
+)
+
+// The errors returned by Nebraska when a request breaks one of its rules
+var (
+	errArchMismatch        = errors.New("nebraska: mismatched arches")
+	errBlacklistedChannel  = errors.New("nebraska: blacklisted channel")
+	errBlacklistingChannel = errors.New("nebraska: channel trying to blacklist is already pointing to the package")
+	errConflict            = errors.New("nebraska: duplicate key value violates unique constraint")
+	errInvalidArch         = errors.New("nebraska: invalid/unsupported arch")
+	errInvalidChannel      = errors.New("nebraska: invalid channel")
+	errInvalidPackage      = errors.New("nebraska: invalid package")
+	errInvalidSemver       = errors.New("nebraska: version is not a valid semver")
+
+	// errNullBlacklist is returned by Nebraska's request validator, before
+	// the request reaches the handler, because channels_blacklist is required
+	// and not nullable in packageConfig
+	errNullBlacklist = errors.New(`request body has an error: doesn't match schema #/components/schemas/packageConfig: Error at "/channels_blacklist": Value is not nullable`)
+)
+
+// Server is a fake Nebraska server that keeps its applications, channels,
+// groups and packages in memory. Like Nebraska it:
+//
+//   - responds with 404 to requests for objects that don't exist, or that
+//     belong to another application
+//   - paginates lists with the page and perpage parameters, and returns them
+//     in the same order
+//   - rejects channels that provide a package with a different arch, or a
+//     package that blacklists them
+//   - rejects packages that blacklist a channel with a different arch, or a
+//     channel that provides them, and packages without a channels_blacklist,
+//     which must be an empty list rather than null or missing
+//   - detaches packages from their channels and channels from their groups
+//     when they're deleted
+//   - reports its version from /config and that it's healthy from /health,
+//     and responds to list requests with arrays when it's older than 2.8.0
+//
+// Requests that break a rule are rejected with a 400 and the reason.
+type Server struct {
+	// URL is the base URL of the server
+	URL string
+
+	server *httptest.Server
+
+	mu       sync.Mutex
+	now      func() time.Time
+	version  string
+	apps     []*nebraska.Application
+	channels []*nebraska.Channel
+	groups   []*nebraska.Group
+	packages []*nebraska.Package
+	stats    map[string]nebraska.GroupInstanceStats
+}
+
+// NewServer starts a fake Nebraska server with the default Flatcar
+// application, without any channels, groups or packages. It should be closed
+// when it's no longer needed.
+func NewServer() *Server {
+	s := &Server{
+		now:     time.Now,
+		version: DefaultVersion,
+		stats:   map[string]nebraska.GroupInstanceStats{},
+	}
+	s.apps = append(s.apps, &nebraska.Application{
+		ID:          nebraska.FlatcarApplicationID,
+		Name:        "Flatcar Container Linux",
+		Description: "Linux for massive server deployments",
+		CreatedTs:   s.now(),
+	})
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /config", s.getConfig)
+	mux.HandleFunc("GET /health", s.getHealth)
+	mux.HandleFunc("GET /api/apps", s.listApplications)
+	mux.HandleFunc("POST /api/apps", s.addApplication)
+	mux.HandleFunc("GET /api/apps/{app}", s.getApplication)
+	mux.HandleFunc("GET /api/apps/{app}/channels", s.listChannels)
+	mux.HandleFunc("POST /api/apps/{app}/channels", s.addChannel)
+	mux.HandleFunc("GET /api/apps/{app}/channels/{id}", s.getChannel)
+	mux.HandleFunc("PUT /api/apps/{app}/channels/{id}", s.updateChannel)
+	mux.HandleFunc("DELETE /api/apps/{app}/channels/{id}", s.deleteChannel)
+	mux.HandleFunc("GET /api/apps/{app}/groups", s.listGroups)
+	mux.HandleFunc("POST /api/apps/{app}/groups", s.addGroup)
+	mux.HandleFunc("GET /api/apps/{app}/groups/{id}", s.getGroup)
+	mux.HandleFunc("PUT /api/apps/{app}/groups/{id}", s.updateGroup)
+	mux.HandleFunc("DELETE /api/apps/{app}/groups/{id}", s.deleteGroup)
+	mux.HandleFunc("GET /api/apps/{app}/groups/{id}/instances_stats", s.getGroupInstanceStats)
+	mux.HandleFunc("GET /api/apps/{app}/packages", s.listPackages)
+	mux.HandleFunc("POST /api/apps/{app}/packages", s.addPackage)
+	mux.HandleFunc("GET /api/apps/{app}/packages/{id}", s.getPackage)
+	mux.HandleFunc("PUT /api/apps/{app}/packages/{id}", s.updatePackage)
+	mux.HandleFunc("DELETE /api/apps/{app}/packages/{id}", s.deletePackage)
+
+	s.server = httptest.NewServer(mux)
+	s.URL = s.server.URL
+
+	return s
+}
+
+// Client returns a client for the server
+func (s *Server) Client() *nebraska.Client {
+	return nebraska.New(s.URL, "nebraskatest", "", "", "")
+}
+
+// Close shuts down the server
+func (s *Server) Close() {
+	s.server.Close()
+}
+
+// SetGroupInstanceStats sets the instance stats that the server returns for
+// the group, which are otherwise all zero
+func (s *Server) SetGroupInstanceStats(groupID string, stats nebraska.GroupInstanceStats) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.stats[groupID] = stats
+}
+
+// SetVersion sets the version of Nebraska that the server reports and
+// behaves like
+func (s *Server) SetVersion(version string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.version = version
+}
+
+func (s *Server) getConfig(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	respond(w, map[string]string{
+		"access_management_url": "",
+		"auth_mode":             "noop",
+		"header_style":          "light",
+		"login_url":             "",
+		"logo":                  "",
+		"logout_url":            "",
+		"nebraska_version":      s.version,
+		"title":                 "",
+	})
+}
+
+func (s *Server) getHealth(w http.ResponseWriter, r *http.Request) {
+	w.Write([]byte("OK"))
+}
+
+// respondPage writes the page of items, or just the items if the server is
+// older than Nebraska 2.8.0
+func (s *Server) respondPage(w http.ResponseWriter, page any, items any) {
+	if s.olderThan("2.8.0") {
+		respond(w, items)
+		return
+	}
+
+	respond(w, page)
+}
+
+// olderThan returns true if the version of Nebraska that the server behaves
+// like is older than the version
+func (s *Server) olderThan(version string) bool {
+	v, err := semver.ParseTolerant(s.version)
+
+	return err == nil && v.LT(semver.MustParse(version))
+}
+
+// application returns the application in the path, by id or product id, or
+// responds with a 404
+func (s *Server) application(w http.ResponseWriter, r *http.Request) *nebraska.Application {
+	id := r.PathValue("app")
+	for _, app := range s.apps {
+		if app.ID == id || (app.ProductID != "" && app.ProductID == id) {
+			return app
+		}
+	}
+	http.NotFound(w, r)
+
+	return nil
+}
+
+// newID returns the id of a new object
+func newID() string {
+	return uuid.NewString()
+}
+
+// deref returns the value that p points to, or the zero value if it's nil
+func deref[T any](p *T) T {
+	if p == nil {
+		var zero T
+		return zero
+	}
+
+	return *p
+}
+
+// paginate returns the page of the items requested by the page and perpage
+// parameters
+func paginate[T any](r *http.Request, items []T) ([]T, error) {
+	page, err := queryInt(r, "page", defaultPage)
+	if err != nil {
+		return nil, err
+	}
+	perPage, err := queryInt(r, "perpage", defaultPerPage)
+	if err != nil {
+		return nil, err
+	}
+
+	start := (page - 1) * perPage
+	if start >= len(items) {
+		return []T{}, nil
+	}
+
+	return items[start:min(start+perPage, len(items))], nil
+}
+
+// queryInt returns the positive integer query parameter, or the default when
+// it's missing or less than 1
+func queryInt(r *http.Request, name string, def int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, err
+	}
+	if i < 1 {
+		return def, nil
+	}
+
+	return i, nil
+}
+
+// decode reads the JSON body of the request into v, or responds with a 400
+func decode(w http.ResponseWriter, r *http.Request, v any) bool {
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return false
+	}
+
+	return true
+}
+
+// respond writes v as the JSON body of the response
+func respond(w http.ResponseWriter, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(v)
+}
+
+// reject responds with a 400 for a request that breaks a rule
+func reject(w http.ResponseWriter, err error) {
+	http.Error(w, err.Error(), http.StatusBadRequest)
+}
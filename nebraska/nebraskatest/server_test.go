@@ -0,0 +1,322 @@
+package nebraskatest
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+	"gotest.tools/assert"
+)
+
+func testPackage(t *testing.T, c *nebraska.Client, version string, arch nebraska.Arch, blacklist ...string) *nebraska.Package {
+	t.Helper()
+
+	if blacklist == nil {
+		blacklist = []string{}
+	}
+
+	pkg, err := c.AddPackage(nebraska.FlatcarApplicationID, &nebraska.AddPackageInput{
+		Arch:              arch,
+		ChannelsBlacklist: blacklist,
+		Type:              nebraska.PackageTypeFlatcar,
+		URL:               "https://update.release.flatcar-linux.net/",
+		Version:           version,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return pkg
+}
+
+func testChannel(t *testing.T, c *nebraska.Client, name string, arch nebraska.Arch, packageID string) *nebraska.Channel {
+	t.Helper()
+
+	ch, err := c.AddChannel(nebraska.FlatcarApplicationID, &nebraska.AddChannelInput{
+		Name:      name,
+		Color:     "#000000",
+		Arch:      arch,
+		PackageID: packageID,
+	})
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return ch
+}
+
+func testChannelPage(t *testing.T, s *Server, query string) *nebraska.ChannelPage {
+	t.Helper()
+
+	resp, err := http.Get(s.URL + "/api/apps/" + nebraska.FlatcarApplicationID + "/channels" + query)
+	if err != nil {
+		t.Fatalf("err: %s", err)
+	}
+	defer resp.Body.Close()
+	page := &nebraska.ChannelPage{}
+	if err := json.NewDecoder(resp.Body).Decode(page); err != nil {
+		t.Fatalf("err: %s", err)
+	}
+
+	return page
+}
+
+func TestServerApplications(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	app, err := c.GetApplication(nebraska.FlatcarApplicationID)
+	assert.NilError(t, err)
+	assert.Equal(t, app.Name, "Flatcar Container Linux")
+
+	productID := "io.example.app"
+	added, err := c.AddApplication(&nebraska.AddApplicationInput{Name: "example", ProductID: &productID})
+	assert.NilError(t, err)
+	app, err = c.GetApplication(productID)
+	assert.NilError(t, err)
+	assert.Equal(t, app.ID, added.ID)
+
+	apps, err := c.ListApplications()
+	assert.NilError(t, err)
+	assert.Equal(t, apps.TotalCount, 2)
+	assert.Equal(t, apps.Applications[0].ID, added.ID)
+
+	_, err = c.GetApplication("missing")
+	assert.Equal(t, err, nebraska.ErrNotFound)
+	_, err = c.ListChannels("missing")
+	assert.Equal(t, err, nebraska.ErrNotFound)
+}
+
+func TestServerChannels(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	appID := nebraska.FlatcarApplicationID
+
+	amd64 := testPackage(t, c, "3602.2.0", nebraska.ArchAMD64)
+	arm64 := testPackage(t, c, "3602.2.0", nebraska.ArchAArch64)
+	ch := testChannel(t, c, "stable", nebraska.ArchAMD64, amd64.ID)
+	assert.Equal(t, ch.PackageID, amd64.ID)
+	assert.Equal(t, ch.Package.Version, "3602.2.0")
+
+	_, err := c.UpdateChannel(appID, ch.ID, &nebraska.UpdateChannelInput{
+		Name:      "stable",
+		Arch:      nebraska.ArchAMD64,
+		PackageID: arm64.ID,
+	})
+	assert.ErrorContains(t, err, "mismatched arches")
+
+	blacklisting := testPackage(t, c, "3510.2.0", nebraska.ArchAMD64, ch.ID)
+	_, err = c.UpdateChannel(appID, ch.ID, &nebraska.UpdateChannelInput{
+		Name:      "stable",
+		Arch:      nebraska.ArchAMD64,
+		PackageID: blacklisting.ID,
+	})
+	assert.ErrorContains(t, err, "blacklisted channel")
+
+	_, err = c.AddChannel(appID, &nebraska.AddChannelInput{Name: "stable", Arch: nebraska.ArchAMD64})
+	assert.ErrorContains(t, err, "unique constraint")
+
+	ch, err = c.GetChannel(appID, ch.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, ch.PackageID, amd64.ID)
+
+	assert.NilError(t, c.DeletePackage(appID, amd64.ID))
+	ch, err = c.GetChannel(appID, ch.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, ch.PackageID, "")
+	assert.Assert(t, ch.Package == nil)
+
+	assert.NilError(t, c.DeleteChannel(appID, ch.ID))
+	_, err = c.GetChannel(appID, ch.ID)
+	assert.Equal(t, err, nebraska.ErrNotFound)
+	assert.Equal(t, c.DeleteChannel(appID, ch.ID), nebraska.ErrNotFound)
+
+	blacklisting, err = c.GetPackage(appID, blacklisting.ID)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, blacklisting.ChannelsBlacklist, []string{})
+}
+
+func TestServerPackages(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	appID := nebraska.FlatcarApplicationID
+
+	for _, v := range []string{"3510.2.1", "3602.2.0", "3510.2.0", "3374.2.5"} {
+		testPackage(t, c, v, nebraska.ArchAMD64)
+	}
+	ch := testChannel(t, c, "stable", nebraska.ArchAMD64, "")
+	arm64 := testChannel(t, c, "stable", nebraska.ArchAArch64, "")
+
+	packages, err := c.ListPackages(appID)
+	assert.NilError(t, err)
+	var versions []string
+	for _, p := range packages.Packages {
+		versions = append(versions, p.Version)
+	}
+	assert.DeepEqual(t, versions, []string{"3602.2.0", "3510.2.1", "3510.2.0", "3374.2.5"})
+
+	packages, err = c.SearchPackages(appID, "3510")
+	assert.NilError(t, err)
+	assert.Equal(t, packages.TotalCount, 2)
+
+	_, err = c.AddPackage(appID, &nebraska.AddPackageInput{Version: "3602.2.1", Arch: nebraska.ArchAMD64})
+	assert.ErrorContains(t, err, "channels_blacklist")
+	_, err = c.AddPackage(appID, &nebraska.AddPackageInput{Version: "3602.2.0", Arch: nebraska.ArchAMD64, ChannelsBlacklist: []string{}})
+	assert.ErrorContains(t, err, "unique constraint")
+	_, err = c.AddPackage(appID, &nebraska.AddPackageInput{Version: "latest", Arch: nebraska.ArchAMD64, ChannelsBlacklist: []string{}})
+	assert.ErrorContains(t, err, "semver")
+	_, err = c.AddPackage(appID, &nebraska.AddPackageInput{Version: "3602.2.1", Arch: nebraska.ArchAMD64, ChannelsBlacklist: []string{arm64.ID}})
+	assert.ErrorContains(t, err, "mismatched arches")
+
+	pkg := packages.Packages[0]
+	_, err = c.UpdateChannel(appID, ch.ID, &nebraska.UpdateChannelInput{Name: "stable", Arch: nebraska.ArchAMD64, PackageID: pkg.ID})
+	assert.NilError(t, err)
+	_, err = c.UpdatePackage(appID, pkg.ID, &nebraska.UpdatePackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{ch.ID},
+		Version:           pkg.Version,
+	})
+	assert.ErrorContains(t, err, "already pointing to the package")
+
+	_, err = c.UpdatePackage(appID, pkg.ID, &nebraska.UpdatePackageInput{
+		Arch:    nebraska.ArchAMD64,
+		Version: pkg.Version,
+	})
+	assert.ErrorContains(t, err, "channels_blacklist")
+
+	updated, err := c.UpdatePackage(appID, pkg.ID, &nebraska.UpdatePackageInput{
+		Arch:              nebraska.ArchAMD64,
+		ChannelsBlacklist: []string{},
+		FlatcarAction:     &nebraska.FlatcarActionInput{Sha256: "abc"},
+		Version:           pkg.Version,
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, updated.FlatcarAction.Sha256, "abc")
+	assert.Equal(t, updated.FlatcarAction.Event, "postinstall")
+
+	_, err = c.GetPackage("missing", pkg.ID)
+	assert.Equal(t, err, nebraska.ErrNotFound)
+}
+
+func TestServerGroups(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	appID := nebraska.FlatcarApplicationID
+
+	ch := testChannel(t, c, "stable", nebraska.ArchAMD64, "")
+	g, err := c.AddGroup(appID, &nebraska.AddGroupInput{
+		Name:                 "workers",
+		ChannelID:            ch.ID,
+		PolicyUpdatesEnabled: true,
+		PolicyTimezone:       "Europe/London",
+		PolicyPeriodInterval: "1 hours",
+		PolicyUpdateTimeout:  "1 days",
+	})
+	assert.NilError(t, err)
+	assert.Equal(t, g.Track, g.ID)
+	assert.Equal(t, g.Channel.Name, "stable")
+
+	_, err = c.AddGroup(appID, &nebraska.AddGroupInput{Name: "other", ChannelID: "missing"})
+	assert.ErrorContains(t, err, "invalid channel")
+
+	stats, err := c.GetGroupInstanceStats(appID, g.ID, "7d")
+	assert.NilError(t, err)
+	assert.Equal(t, stats.Total, 0)
+	s.SetGroupInstanceStats(g.ID, nebraska.GroupInstanceStats{Total: 3, Complete: 3})
+	stats, err = c.GetGroupInstanceStats(appID, g.ID, "7d")
+	assert.NilError(t, err)
+	assert.Equal(t, stats.Total, 3)
+
+	assert.NilError(t, c.DeleteChannel(appID, ch.ID))
+	g, err = c.GetGroup(appID, g.ID)
+	assert.NilError(t, err)
+	assert.Equal(t, g.ChannelID, "")
+	assert.Assert(t, g.Channel == nil)
+
+	assert.NilError(t, c.DeleteGroup(appID, g.ID))
+	_, err = c.GetGroup(appID, g.ID)
+	assert.Equal(t, err, nebraska.ErrNotFound)
+}
+
+func TestServerPagination(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	for _, name := range []string{"e", "d", "c", "b", "a"} {
+		testChannel(t, c, name, nebraska.ArchAMD64, "")
+	}
+
+	page := testChannelPage(t, s, "?page=2&perpage=2")
+	assert.Equal(t, page.Count, 2)
+	assert.Equal(t, page.TotalCount, 5)
+	var names []string
+	for _, ch := range page.Channels {
+		names = append(names, ch.Name)
+	}
+	assert.Equal(t, strings.Join(names, ","), "c,d")
+
+	// Without any pagination Nebraska returns the first 10
+	for i := 0; i < 10; i++ {
+		testChannel(t, c, "channel-"+string(rune('a'+i)), nebraska.ArchAMD64, "")
+	}
+	page = testChannelPage(t, s, "")
+	assert.Equal(t, page.Count, 10)
+	assert.Equal(t, page.TotalCount, 15)
+}
+
+func TestServerVersions(t *testing.T) {
+	for _, version := range []string{"2.7.0", "2.8.0", DefaultVersion} {
+		t.Run(version, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+			s.SetVersion(version)
+			c := s.Client()
+
+			v, err := c.ServerVersion()
+			assert.NilError(t, err)
+			assert.Equal(t, v.String(), version)
+			capabilities, err := c.Capabilities()
+			assert.NilError(t, err)
+			assert.Equal(t, capabilities.AuthMode, "noop")
+			assert.Equal(t, capabilities.Supports(nebraska.FeaturePackageSearch), version != "2.7.0")
+
+			pkg := testPackage(t, c, "1.0.0", nebraska.ArchAMD64)
+			ch := testChannel(t, c, "stable", nebraska.ArchAMD64, pkg.ID)
+
+			apps, err := c.ListApplications()
+			assert.NilError(t, err)
+			assert.Equal(t, apps.TotalCount, 1)
+			assert.Equal(t, apps.Applications[0].ID, nebraska.FlatcarApplicationID)
+			channels, err := c.ListChannels(nebraska.FlatcarApplicationID)
+			assert.NilError(t, err)
+			assert.Equal(t, channels.TotalCount, 1)
+			assert.Equal(t, channels.Channels[0].ID, ch.ID)
+			groups, err := c.ListGroups(nebraska.FlatcarApplicationID)
+			assert.NilError(t, err)
+			assert.Equal(t, groups.TotalCount, 0)
+			packages, err := c.ListPackages(nebraska.FlatcarApplicationID)
+			assert.NilError(t, err)
+			assert.Equal(t, packages.TotalCount, 1)
+			assert.Equal(t, packages.Packages[0].ID, pkg.ID)
+		})
+	}
+
+	for _, version := range []string{"2.6.0", "3.0.0", "dev"} {
+		t.Run(version, func(t *testing.T) {
+			s := NewServer()
+			defer s.Close()
+			s.SetVersion(version)
+
+			_, err := s.Client().ListApplications()
+			assert.Assert(t, errors.Is(err, nebraska.ErrUnsupportedServerVersion), "got %v", err)
+		})
+	}
+}
@@ -0,0 +1,262 @@
+package nebraska
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+const (
+	// omahaPath is the path of Nebraska's Omaha update endpoint
+	omahaPath = "/v1/update/"
+
+	// omahaProtocolVersion is the version of the Omaha protocol spoken by
+	// the client
+	omahaProtocolVersion = "3.0"
+)
+
+var (
+	// ErrInvalidOmahaResponse is returned when the Omaha response doesn't
+	// contain the requested application
+	ErrInvalidOmahaResponse = errors.New("nebraska: invalid omaha response")
+
+	// omahaArchs maps the archs that Nebraska supports to the os arch and
+	// board that Flatcar machines report in their Omaha requests
+	// https://github.com/kinvolk/nebraska/blob/main/backend/pkg/api/arch.go#L36-L42
+	omahaArchs = map[string]struct {
+		arch  string
+		board string
+	}{
+		"amd64":   {arch: "x64", board: "amd64-usr"},
+		"aarch64": {arch: "arm", board: "arm64-usr"},
+		"x86":     {arch: "x86"},
+	}
+)
+
+type omahaRequest struct {
+	XMLName       xml.Name          `xml:"request"`
+	Protocol      string            `xml:"protocol,attr"`
+	InstallSource string            `xml:"installsource,attr,omitempty"`
+	IsMachine     int               `xml:"ismachine,attr,omitempty"`
+	OS            omahaOS           `xml:"os"`
+	Apps          []omahaAppRequest `xml:"app"`
+}
+
+type omahaOS struct {
+	Platform string `xml:"platform,attr,omitempty"`
+	Arch     string `xml:"arch,attr,omitempty"`
+}
+
+type omahaAppRequest struct {
+	ID          string              `xml:"appid,attr"`
+	Version     string              `xml:"version,attr,omitempty"`
+	Track       string              `xml:"track,attr,omitempty"`
+	Board       string              `xml:"board,attr,omitempty"`
+	MachineID   string              `xml:"machineid,attr,omitempty"`
+	UpdateCheck *omahaUpdateRequest `xml:"updatecheck"`
+}
+
+type omahaUpdateRequest struct{}
+
+type omahaResponse struct {
+	XMLName  xml.Name           `xml:"response"`
+	Protocol string             `xml:"protocol,attr"`
+	Server   string             `xml:"server,attr"`
+	Apps     []omahaAppResponse `xml:"app"`
+}
+
+type omahaAppResponse struct {
+	ID          string               `xml:"appid,attr"`
+	Status      string               `xml:"status,attr"`
+	UpdateCheck *omahaUpdateResponse `xml:"updatecheck"`
+}
+
+type omahaUpdateResponse struct {
+	Status   string         `xml:"status,attr"`
+	URLs     []omahaURL     `xml:"urls>url"`
+	Manifest *omahaManifest `xml:"manifest"`
+}
+
+type omahaURL struct {
+	CodeBase string `xml:"codebase,attr"`
+}
+
+type omahaManifest struct {
+	Version  string         `xml:"version,attr"`
+	Packages []omahaPackage `xml:"packages>package"`
+	Actions  []omahaAction  `xml:"actions>action"`
+}
+
+type omahaPackage struct {
+	Name     string `xml:"name,attr"`
+	SHA1     string `xml:"hash,attr"`
+	SHA256   string `xml:"hash_sha256,attr"`
+	Size     uint64 `xml:"size,attr"`
+	Required bool   `xml:"required,attr"`
+}
+
+type omahaAction struct {
+	Event  string `xml:"event,attr"`
+	SHA256 string `xml:"sha256,attr"`
+}
+
+// UpdateCheckInput are the supported arguments when performing an update
+// check
+type UpdateCheckInput struct {
+	AppID     string
+	Track     string
+	Version   string
+	Arch      string
+	MachineID string
+}
+
+// UpdateCheck is the outcome of an update check
+type UpdateCheck struct {
+	// AppStatus is the status of the application in the response. Anything
+	// other than "ok" explains why Nebraska refused to look for an update.
+	AppStatus string
+	// Status is the status of the update check: "ok" when an update is
+	// offered, "noupdate" when there isn't one or an error code otherwise.
+	Status string
+	// UpdateAvailable indicates whether an update is offered
+	UpdateAvailable bool
+	// Version is the version that is offered
+	Version string
+	// URL is the address of the offered payload
+	URL string
+	// Hash is the base64 encoded sha1 hash of the offered payload
+	Hash string
+	// SHA256 is the base64 encoded sha256 hash of the offered payload
+	SHA256 string
+	// Size is the size of the offered payload, in bytes
+	Size uint64
+}
+
+// UpdateCheck performs an Omaha update check as a machine with the given
+// properties would. Note that Nebraska registers the machine as an instance of
+// the group as a side effect.
+func (c *Client) UpdateCheck(input *UpdateCheckInput) (*UpdateCheck, error) {
+	arch, ok := omahaArchs[input.Arch]
+	if !ok {
+		return nil, ErrInvalidArch
+	}
+
+	body := &omahaRequest{
+		Protocol:      omahaProtocolVersion,
+		InstallSource: "scheduler",
+		IsMachine:     1,
+		OS: omahaOS{
+			Platform: "CoreOS",
+			Arch:     arch.arch,
+		},
+		Apps: []omahaAppRequest{
+			{
+				ID:          "{" + input.AppID + "}",
+				Version:     input.Version,
+				Track:       input.Track,
+				Board:       arch.board,
+				MachineID:   input.MachineID,
+				UpdateCheck: &omahaUpdateRequest{},
+			},
+		},
+	}
+
+	req, err := c.newOmahaRequest(body)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &omahaResponse{}
+	if err := c.doOmaha(req, resp); err != nil {
+		return nil, err
+	}
+
+	for _, app := range resp.Apps {
+		if !strings.EqualFold(strings.Trim(app.ID, "{}"), input.AppID) {
+			continue
+		}
+
+		return parseUpdateCheck(&app), nil
+	}
+
+	return nil, ErrInvalidOmahaResponse
+}
+
+func parseUpdateCheck(app *omahaAppResponse) *UpdateCheck {
+	check := &UpdateCheck{
+		AppStatus: app.Status,
+	}
+	if app.UpdateCheck == nil {
+		return check
+	}
+	check.Status = app.UpdateCheck.Status
+	if check.Status != "ok" || app.UpdateCheck.Manifest == nil {
+		return check
+	}
+
+	check.UpdateAvailable = true
+	check.Version = app.UpdateCheck.Manifest.Version
+	for _, pkg := range app.UpdateCheck.Manifest.Packages {
+		if !pkg.Required {
+			continue
+		}
+		if len(app.UpdateCheck.URLs) > 0 {
+			check.URL = app.UpdateCheck.URLs[0].CodeBase + pkg.Name
+		}
+		check.Hash = pkg.SHA1
+		check.SHA256 = pkg.SHA256
+		check.Size = pkg.Size
+		break
+	}
+	for _, action := range app.UpdateCheck.Manifest.Actions {
+		if action.Event == "postinstall" && action.SHA256 != "" {
+			check.SHA256 = action.SHA256
+		}
+	}
+
+	return check
+}
+
+func (c *Client) newOmahaRequest(body *omahaRequest) (*http.Request, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	if err := xml.NewEncoder(&buf).Encode(body); err != nil {
+		return nil, err
+	}
+	req, err := http.NewRequestWithContext(c.ctx, http.MethodPost, fmt.Sprintf("%s%s", c.BaseURL, omahaPath), &buf)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Add("User-Agent", c.userAgent)
+	req.Header.Add("Content-Type", "text/xml")
+
+	return req, nil
+}
+
+func (c *Client) doOmaha(req *http.Request, data *omahaResponse) error {
+	resp, err := c.roundTrip(req)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		io.Copy(ioutil.Discard, resp.Body)
+		resp.Body.Close()
+	}()
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("Bad response: req_uri=%s, response_code=%d, response=%s", req.URL.String(), resp.StatusCode, string(body))
+	}
+	if err := xml.Unmarshal(body, data); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidOmahaResponse, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,111 @@
+package nebraska
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+const testOmahaUpdateResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<response protocol="3.0" server="nebraska">
+  <daystart elapsed_seconds="0"></daystart>
+  <app appid="{%s}" status="ok">
+    <updatecheck status="ok">
+      <urls>
+        <url codebase="https://update.release.flatcar-linux.net/amd64-usr/3510.2.0/"></url>
+      </urls>
+      <manifest version="3510.2.0">
+        <packages>
+          <package name="flatcar_production_update.gz" hash="r3nufcxgMTZaxYEqL+x2zIoeClk=" size="465881871" required="true"></package>
+        </packages>
+        <actions>
+          <action event="postinstall" sha256="LIkAKVZY2EJFiwTmltiJZLFLA5xT/FodbjVgqkyF/y8=" needsadmin="false"></action>
+        </actions>
+      </manifest>
+    </updatecheck>
+  </app>
+</response>`
+
+const testOmahaRefusedResponse = `<?xml version="1.0" encoding="UTF-8"?>
+<response protocol="3.0" server="nebraska">
+  <daystart elapsed_seconds="0"></daystart>
+  <app appid="{%s}" status="error-updatesDisabled">
+    <updatecheck status="error-internal"></updatecheck>
+  </app>
+</response>`
+
+func TestClientUpdateCheck(t *testing.T) {
+	c, s := testClientServer("", "", "", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, r.Method, http.MethodPost)
+		assert.Equal(t, r.URL.Path, "/v1/update/")
+		assert.Equal(t, r.Header.Get("Content-Type"), "text/xml")
+
+		req := &omahaRequest{}
+		if err := xml.NewDecoder(r.Body).Decode(req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, req.Protocol, "3.0")
+		assert.Equal(t, req.OS.Arch, "x64")
+		assert.Equal(t, len(req.Apps), 1)
+		assert.Equal(t, req.Apps[0].ID, "{"+FlatcarApplicationID+"}")
+		assert.Equal(t, req.Apps[0].Version, "3374.2.0")
+		assert.Equal(t, req.Apps[0].Board, "amd64-usr")
+		assert.Equal(t, req.Apps[0].MachineID, "test-machine")
+		assert.Assert(t, req.Apps[0].UpdateCheck != nil)
+
+		w.Header().Set("Content-Type", "text/xml")
+		switch req.Apps[0].Track {
+		case "stable":
+			fmt.Fprintf(w, testOmahaUpdateResponse, req.Apps[0].ID[1:len(req.Apps[0].ID)-1])
+		default:
+			fmt.Fprintf(w, testOmahaRefusedResponse, req.Apps[0].ID[1:len(req.Apps[0].ID)-1])
+		}
+	})
+	defer s.Close()
+
+	check, err := c.UpdateCheck(&UpdateCheckInput{
+		AppID:     FlatcarApplicationID,
+		Track:     "stable",
+		Version:   "3374.2.0",
+		Arch:      "amd64",
+		MachineID: "test-machine",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, check, &UpdateCheck{
+		AppStatus:       "ok",
+		Status:          "ok",
+		UpdateAvailable: true,
+		Version:         "3510.2.0",
+		URL:             "https://update.release.flatcar-linux.net/amd64-usr/3510.2.0/flatcar_production_update.gz",
+		Hash:            "r3nufcxgMTZaxYEqL+x2zIoeClk=",
+		SHA256:          "LIkAKVZY2EJFiwTmltiJZLFLA5xT/FodbjVgqkyF/y8=",
+		Size:            465881871,
+	})
+
+	check, err = c.UpdateCheck(&UpdateCheckInput{
+		AppID:     FlatcarApplicationID,
+		Track:     "disabled",
+		Version:   "3374.2.0",
+		Arch:      "amd64",
+		MachineID: "test-machine",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assert.DeepEqual(t, check, &UpdateCheck{
+		AppStatus: "error-updatesDisabled",
+		Status:    "error-internal",
+	})
+
+	_, err = c.UpdateCheck(&UpdateCheckInput{
+		AppID: FlatcarApplicationID,
+		Arch:  "all",
+	})
+	assert.Equal(t, err, ErrInvalidArch)
+}
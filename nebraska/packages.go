@@ -4,8 +4,6 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
-
-	"github.com/kinvolk/nebraska/backend/pkg/codegen"
 )
 
 // PackageType is the type of package
@@ -56,13 +54,13 @@ func PackageTypeFromString(s string) (PackageType, error) {
 }
 
 // GetPackage retrieves a package by its id
-func (c *Client) GetPackage(appID, id string) (*codegen.Package, error) {
+func (c *Client) GetPackage(appID, id string) (*Package, error) {
 	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s/packages/%s", appID, id), nil)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Package{}
+	data := &Package{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -70,14 +68,35 @@ func (c *Client) GetPackage(appID, id string) (*codegen.Package, error) {
 	return data, nil
 }
 
-// SearchPackages lists the packages for a particular application and version
-func (c *Client) SearchPackages(appID, id string) (*codegen.PackagePage, error) {
-	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s/packages?page=1&perpage=100000&searchVersion=%s", appID, id), nil)
+// ListPackages lists all the packages for a particular application
+func (c *Client) ListPackages(appID string) (*PackagePage, error) {
+	req, err := c.newRequest(http.MethodGet, fmt.Sprintf("/api/apps/%s/packages?page=1&perpage=100000", appID), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data := &PackagePage{}
+	if err := c.do(req, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+// SearchPackages lists the packages for a particular application whose
+// versions contain the search. Servers without FeaturePackageSearch return all
+// of the packages, so callers should filter them too.
+func (c *Client) SearchPackages(appID, search string) (*PackagePage, error) {
+	path := fmt.Sprintf("/api/apps/%s/packages?page=1&perpage=100000", appID)
+	if c.supports(FeaturePackageSearch) {
+		path += "&searchVersion=" + search
+	}
+	req, err := c.newRequest(http.MethodGet, path, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.PackagePage{}
+	data := &PackagePage{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -88,7 +107,7 @@ func (c *Client) SearchPackages(appID, id string) (*codegen.PackagePage, error)
 // AddPackageInput are the supported arguments when adding a package
 type AddPackageInput struct {
 	ApplicationID     string              `json:"application_id"`
-	Arch              codegen.Arch        `json:"arch"`
+	Arch              Arch                `json:"arch"`
 	ChannelsBlacklist []string            `json:"channels_blacklist"`
 	Description       string              `json:"description"`
 	Filename          string              `json:"filename"`
@@ -107,13 +126,13 @@ type FlatcarActionInput struct {
 }
 
 // AddPackage adds a new package
-func (c *Client) AddPackage(appID string, input *AddPackageInput) (*codegen.Package, error) {
+func (c *Client) AddPackage(appID string, input *AddPackageInput) (*Package, error) {
 	req, err := c.newRequest(http.MethodPost, fmt.Sprintf("/api/apps/%s/packages", appID), input)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Package{}
+	data := &Package{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -124,7 +143,7 @@ func (c *Client) AddPackage(appID string, input *AddPackageInput) (*codegen.Pack
 // UpdatePackageInput are the supported arguments when updating a package
 type UpdatePackageInput struct {
 	ApplicationID     string              `json:"application_id"`
-	Arch              codegen.Arch        `json:"arch"`
+	Arch              Arch                `json:"arch"`
 	ChannelsBlacklist []string            `json:"channels_blacklist"`
 	Description       string              `json:"description"`
 	Filename          string              `json:"filename"`
@@ -137,13 +156,13 @@ type UpdatePackageInput struct {
 }
 
 // UpdatePackage updates an existing package
-func (c *Client) UpdatePackage(appID, id string, input *UpdatePackageInput) (*codegen.Package, error) {
+func (c *Client) UpdatePackage(appID, id string, input *UpdatePackageInput) (*Package, error) {
 	req, err := c.newRequest(http.MethodPut, fmt.Sprintf("/api/apps/%s/packages/%s", appID, id), input)
 	if err != nil {
 		return nil, err
 	}
 
-	data := &codegen.Package{}
+	data := &Package{}
 	if err := c.do(req, data); err != nil {
 		return nil, err
 	}
@@ -0,0 +1,105 @@
+package nebraska
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the client's spans
+const tracerName = "github.com/utilitywarehouse/terraform-provider-nebraska/nebraska"
+
+// routeParams are the placeholders of the ids that follow each collection in
+// the paths of the Nebraska API
+var routeParams = map[string]string{
+	"apps":     "{app}",
+	"channels": "{id}",
+	"groups":   "{id}",
+	"packages": "{id}",
+}
+
+// SetTracerProvider traces the requests of the client with the provider
+// instead of the global one
+func (c *Client) SetTracerProvider(tp trace.TracerProvider) {
+	c.tracerProvider = tp
+}
+
+func (c *Client) tracer() trace.Tracer {
+	tp := c.tracerProvider
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+
+	return tp.Tracer(tracerName)
+}
+
+// routeTemplate returns the path with its ids replaced by placeholders, such
+// as /api/apps/{app}/channels/{id}, so that the spans of requests for
+// different objects are grouped together
+func routeTemplate(path string) string {
+	segments := strings.Split(path, "/")
+	for i := 1; i < len(segments); i++ {
+		if param, ok := routeParams[segments[i-1]]; ok && segments[i] != "" {
+			segments[i] = param
+		}
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// startSpan starts a client span for the request and returns the request
+// with the span in its context and its trace context in the traceparent
+// header, so that Nebraska can continue the trace
+func (c *Client) startSpan(req *http.Request) (*http.Request, trace.Span) {
+	route := routeTemplate(req.URL.Path)
+	attrs := []attribute.KeyValue{
+		semconv.HTTPRequestMethodKey.String(req.Method),
+		semconv.HTTPRoute(route),
+		semconv.URLFull(redactedURL(req)),
+		semconv.ServerAddress(req.URL.Hostname()),
+		// The client doesn't retry requests, so each is the first attempt
+		semconv.HTTPRequestResendCount(0),
+	}
+	if port, err := strconv.Atoi(req.URL.Port()); err == nil {
+		attrs = append(attrs, semconv.ServerPort(port))
+	}
+	ctx, span := c.tracer().Start(req.Context(), req.Method+" "+route,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attrs...),
+	)
+
+	req = req.WithContext(ctx)
+	propagation.TraceContext{}.Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	return req, span
+}
+
+// endSpan records the outcome of the request on its span and ends it
+func endSpan(span trace.Span, resp *http.Response, err error) {
+	defer span.End()
+
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return
+	}
+	span.SetAttributes(semconv.HTTPResponseStatusCode(resp.StatusCode))
+	if resp.StatusCode >= 400 {
+		span.SetStatus(codes.Error, http.StatusText(resp.StatusCode))
+	}
+}
+
+// redactedURL returns the URL of the request without any credentials in it
+func redactedURL(req *http.Request) string {
+	u := *req.URL
+	u.User = nil
+
+	return u.String()
+}
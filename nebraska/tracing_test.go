@@ -0,0 +1,72 @@
+package nebraska
+
+import (
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"gotest.tools/assert"
+)
+
+func TestClientTracing(t *testing.T) {
+	var traceparents []string
+	c, s := testClientServer("", "", "", func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		if r.URL.Path == "/api/apps/app/channels/missing" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{"id":"ch","application_id":"app"}`))
+	})
+	defer s.Close()
+	exporter := tracetest.NewInMemoryExporter()
+	c.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+
+	_, err := c.GetChannel("app", "ch")
+	assert.NilError(t, err)
+	_, err = c.GetChannel("app", "missing")
+	assert.Equal(t, err, ErrNotFound)
+
+	spans := exporter.GetSpans()
+	assert.Equal(t, len(spans), 3)
+	assert.Equal(t, spans[0].Name, "GET /config")
+	for i, span := range spans[1:] {
+		assert.Equal(t, span.Name, "GET /api/apps/{app}/channels/{id}")
+		assert.Equal(t, spanAttribute(span, "http.route").AsString(), "/api/apps/{app}/channels/{id}")
+		assert.Equal(t, spanAttribute(span, "http.request.method").AsString(), http.MethodGet)
+		assert.Equal(t, spanAttribute(span, "http.request.resend_count").AsInt64(), int64(0))
+		assert.Equal(t, traceparents[i], "00-"+span.SpanContext.TraceID().String()+"-"+span.SpanContext.SpanID().String()+"-01")
+	}
+	assert.Equal(t, spanAttribute(spans[1], "http.response.status_code").AsInt64(), int64(http.StatusOK))
+	assert.Equal(t, spans[1].Status.Code, codes.Unset)
+	assert.Equal(t, spanAttribute(spans[2], "http.response.status_code").AsInt64(), int64(http.StatusNotFound))
+	assert.Equal(t, spans[2].Status.Code, codes.Error)
+}
+
+func spanAttribute(span tracetest.SpanStub, key attribute.Key) attribute.Value {
+	for _, kv := range span.Attributes {
+		if kv.Key == key {
+			return kv.Value
+		}
+	}
+
+	return attribute.Value{}
+}
+
+func TestRouteTemplate(t *testing.T) {
+	for path, want := range map[string]string{
+		"/":                                      "/",
+		"/config":                                "/config",
+		"/api/apps":                              "/api/apps",
+		"/api/apps/app":                          "/api/apps/{app}",
+		"/api/apps/app/channels":                 "/api/apps/{app}/channels",
+		"/api/apps/app/channels/ch":              "/api/apps/{app}/channels/{id}",
+		"/api/apps/app/packages/p":               "/api/apps/{app}/packages/{id}",
+		"/api/apps/app/groups/g/instances_stats": "/api/apps/{app}/groups/{id}/instances_stats",
+	} {
+		assert.Equal(t, routeTemplate(path), want, path)
+	}
+}
@@ -0,0 +1,23 @@
+package nebraska
+
+import (
+	"fmt"
+
+	"github.com/blang/semver/v4"
+)
+
+// CompareVersions compares two package versions the way Nebraska does when it
+// decides whether to offer an update. It returns -1, 0 or 1 when a is older
+// than, the same as or newer than b.
+func CompareVersions(a, b string) (int, error) {
+	va, err := semver.Make(a)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", a, err)
+	}
+	vb, err := semver.Make(b)
+	if err != nil {
+		return 0, fmt.Errorf("invalid version %q: %w", b, err)
+	}
+
+	return va.Compare(vb), nil
+}
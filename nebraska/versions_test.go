@@ -0,0 +1,27 @@
+package nebraska
+
+import (
+	"testing"
+
+	"gotest.tools/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	for _, tc := range []struct {
+		a, b string
+		want int
+	}{
+		{"3510.2.1", "3510.2.1", 0},
+		{"3510.2.0", "3510.2.1", -1},
+		{"3602.2.0", "3510.3.0", 1},
+		{"3510.2.0", "3510.2.0-rc1", 1},
+		{"3510.10.0", "3510.9.0", 1},
+	} {
+		got, err := CompareVersions(tc.a, tc.b)
+		assert.NilError(t, err)
+		assert.Equal(t, got, tc.want, "%s <=> %s", tc.a, tc.b)
+	}
+
+	_, err := CompareVersions("3510.2", "3510.2.1")
+	assert.ErrorContains(t, err, `invalid version "3510.2"`)
+}
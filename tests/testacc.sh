@@ -3,27 +3,47 @@
 set -e
 
 TIMEOUT=${TIMEOUT:-30}
+# The Nebraska releases to run the acceptance tests against, which should
+# cover the range in nebraska.SupportedServerVersions
+NEBRASKA_VERSIONS=${NEBRASKA_VERSIONS:-"2.7.0 2.8.0 2.9.0"}
 
-docker compose -f $PWD/tests/docker-compose.yml up -d
+COMPOSE="docker compose -f $PWD/tests/docker-compose.yml"
 
-trap "docker compose -f $PWD/tests/docker-compose.yml down" EXIT
+trap "$COMPOSE down" EXIT
 
-echo "Waiting for Nebraska to be up"
-i=0
-while true; do
-  if curl -sSf -o /dev/null http://localhost:8000/api/apps 2>/dev/null; then
-    printf "\n"
-    break
-  fi
-  i=$((i+1))
-  if [ $i -ge $TIMEOUT ]; then
-    echo "Couldn't reach Nebraska within the timeout: ${TIMEOUT}s" >&2
+for version in $NEBRASKA_VERSIONS; do
+  echo "Running the acceptance tests against Nebraska $version"
+
+  NEBRASKA_VERSION=$version $COMPOSE up -d --force-recreate
+
+  echo "Waiting for Nebraska to be up"
+  i=0
+  while true; do
+    if curl -sSf -o /dev/null http://localhost:8000/api/apps 2>/dev/null; then
+      printf "\n"
+      break
+    fi
+    i=$((i+1))
+    if [ $i -ge $TIMEOUT ]; then
+      echo "Couldn't reach Nebraska $version within the timeout: ${TIMEOUT}s" >&2
+      exit 1
+    fi
+    printf "."
+    sleep 1
+  done
+
+  # The fixtures are recorded once, without credentials, so the runs with
+  # them are left out when recording
+  if ! {
+    TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 go test ./... -v -timeout 120m &&
+    { [ "$NEBRASKA_TEST_MODE" = record ] || {
+      TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 NEBRASKA_USERNAME=user NEBRASKA_PASSWORD=pass go test ./nebraska -v -timeout 120m &&
+      TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 NEBRASKA_BEARER_TOKEN=token go test ./nebraska -v -timeout 120m
+    }; }
+  }; then
+    echo "The acceptance tests failed against Nebraska $version" >&2
     exit 1
   fi
-  printf "."
-  sleep 1
-done
 
-TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 go test ./... -v -timeout 120m
-TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 NEBRASKA_USERNAME=user NEBRASKA_PASSWORD=pass go test ./nebraska -v -timeout 120m
-TF_ACC=true NEBRASKA_ENDPOINT=http://localhost:8000 NEBRASKA_BEARER_TOKEN=token go test ./nebraska -v -timeout 120m
+  $COMPOSE down
+done